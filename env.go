@@ -0,0 +1,212 @@
+package main
+
+import (
+	"context"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/oidc"
+	"github.com/HellYeahOmg/Chirpy/internal/throttle"
+)
+
+func envInt(name string, fallback int) int {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		log.Printf("invalid %s %q, using %d: %s", name, value, fallback, err)
+		return fallback
+	}
+	return parsed
+}
+
+func envDuration(name string, fallback time.Duration) time.Duration {
+	value := os.Getenv(name)
+	if value == "" {
+		return fallback
+	}
+
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		log.Printf("invalid %s %q, using %s: %s", name, value, fallback, err)
+		return fallback
+	}
+	return parsed
+}
+
+// envTime reads an RFC 3339 timestamp, or the zero time when name is unset.
+func envTime(name string) time.Time {
+	value := os.Getenv(name)
+	if value == "" {
+		return time.Time{}
+	}
+
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		log.Fatalf("invalid %s %q, must be an RFC 3339 time such as 2025-01-31T00:00:00Z: %s", name, value, err)
+	}
+	return parsed
+}
+
+// newLoginLimiters builds the per-account and per-address login throttles.
+func newLoginLimiters(db *database.Queries) (*throttle.Limiter, *throttle.Limiter) {
+	window := envDuration("LOGIN_THROTTLE_WINDOW", 15*time.Minute)
+	lockout := envDuration("LOGIN_LOCKOUT_DURATION", 15*time.Minute)
+	// Failures older than the window are forgotten, which would lift a
+	// longer lockout early.
+	if lockout > window {
+		log.Fatalf("invalid LOGIN_LOCKOUT_DURATION %s, must not be longer than LOGIN_THROTTLE_WINDOW %s", lockout, window)
+	}
+
+	emailPolicy := throttle.Policy{
+		Window:           window,
+		FreeAttempts:     envInt("LOGIN_FREE_ATTEMPTS", 3),
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: envInt("LOGIN_LOCKOUT_THRESHOLD", 10),
+		LockoutDuration:  lockout,
+	}
+
+	ipPolicy := throttle.Policy{
+		Window:           window,
+		FreeAttempts:     envInt("LOGIN_IP_FREE_ATTEMPTS", 20),
+		BaseDelay:        time.Second,
+		MaxDelay:         time.Minute,
+		LockoutThreshold: envInt("LOGIN_IP_LOCKOUT_THRESHOLD", 100),
+		LockoutDuration:  lockout,
+	}
+
+	store := newThrottleStore(db, window)
+	return throttle.NewLimiter(store, emailPolicy), throttle.NewLimiter(store, ipPolicy)
+}
+
+// newMagicLinkLimiter builds the throttle for magic link requests. It counts
+// every request, so the first MAGIC_LINK_FREE_REQUESTS per hour go out right
+// away and later ones have to wait longer and longer.
+func newMagicLinkLimiter(db *database.Queries) *throttle.Limiter {
+	policy := throttle.Policy{
+		Window:           time.Hour,
+		FreeAttempts:     envInt("MAGIC_LINK_FREE_REQUESTS", 3),
+		BaseDelay:        time.Minute,
+		MaxDelay:         15 * time.Minute,
+		LockoutThreshold: envInt("MAGIC_LINK_LOCKOUT_THRESHOLD", 10),
+		LockoutDuration:  time.Hour,
+	}
+
+	return throttle.NewLimiter(newThrottleStore(db, policy.Window), policy)
+}
+
+// newThrottleStore keeps throttle state in memory, or in Postgres with
+// LOGIN_THROTTLE_STORE=postgres so it is shared between instances. Either
+// way, failures past the retention are pruned in the background so keys
+// that are never tried again don't pile up.
+func newThrottleStore(db *database.Queries, retention time.Duration) throttle.Store {
+	var store throttle.Store = throttle.NewMemoryStore(retention)
+	if os.Getenv("LOGIN_THROTTLE_STORE") == "postgres" {
+		store = throttle.NewPostgresStore(db, retention)
+	}
+
+	go throttle.PruneEvery(context.Background(), store, envDuration("LOGIN_THROTTLE_PRUNE_INTERVAL", 5*time.Minute))
+	return store
+}
+
+// trustedProxies reads how many proxies append to X-Forwarded-For.
+// TRUST_PROXY_HEADERS=true on its own means a single proxy.
+func trustedProxies() int {
+	if os.Getenv("TRUST_PROXY_HEADERS") != "true" {
+		return 0
+	}
+
+	hops := envInt("TRUSTED_PROXY_HOPS", 1)
+	if hops < 1 {
+		log.Fatalf("invalid TRUSTED_PROXY_HOPS %d, must be at least 1", hops)
+	}
+	return hops
+}
+
+// newPasswordHasher reads the argon2id parameters. Out of range values stop
+// the server right away; argon2 would otherwise panic on the first login, or
+// the parallelism would silently wrap around.
+func newPasswordHasher() *auth.PasswordHasher {
+	params := auth.DefaultPasswordParams
+	params.Memory = uint32(envIntRange("PASSWORD_ARGON2_MEMORY_KIB", int64(params.Memory), 1, math.MaxUint32))
+	params.Iterations = uint32(envIntRange("PASSWORD_ARGON2_ITERATIONS", int64(params.Iterations), 1, math.MaxUint32))
+	params.Parallelism = uint8(envIntRange("PASSWORD_ARGON2_PARALLELISM", int64(params.Parallelism), 1, math.MaxUint8))
+	return auth.NewPasswordHasher(params)
+}
+
+// envIntRange reads a setting that can't work outside of lo..hi. It parses
+// into an int64 so bounds such as math.MaxUint32 fit on 32-bit platforms.
+func envIntRange(name string, fallback, lo, hi int64) int64 {
+	value := fallback
+	if raw := os.Getenv(name); raw != "" {
+		parsed, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			log.Fatalf("invalid %s %q: %s", name, raw, err)
+		}
+		value = parsed
+	}
+
+	if value < lo || value > hi {
+		log.Fatalf("invalid %s %d, must be between %d and %d", name, value, lo, hi)
+	}
+	return value
+}
+
+// newPasswordPolicy builds the policy for new passwords. PASSWORD_BANNED_FILE
+// extends the built-in list of common passwords, and PASSWORD_BREACHED_DIR
+// points at a local copy of the Have I Been Pwned range files.
+func newPasswordPolicy() (*auth.PasswordPolicy, error) {
+	policy := auth.NewPasswordPolicy(envInt("PASSWORD_MIN_LENGTH", 8), envInt("PASSWORD_MAX_LENGTH", 128))
+
+	if path := os.Getenv("PASSWORD_BANNED_FILE"); path != "" {
+		if err := policy.LoadBannedPasswords(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if dir := os.Getenv("PASSWORD_BREACHED_DIR"); dir != "" {
+		policy.Breached = auth.HIBPDirectory{Dir: dir}
+	}
+
+	return policy, nil
+}
+
+// newOIDCProvider discovers the identity provider at OIDC_ISSUER. It returns
+// nil when OIDC login isn't configured.
+func newOIDCProvider(baseURL string) (*oidc.Provider, error) {
+	issuer := os.Getenv("OIDC_ISSUER")
+	if issuer == "" {
+		return nil, nil
+	}
+
+	redirectURL := os.Getenv("OIDC_REDIRECT_URL")
+	if redirectURL == "" {
+		redirectURL = baseURL + "/api/login/oidc/callback"
+	}
+
+	scopes := strings.Fields(os.Getenv("OIDC_SCOPES"))
+	if len(scopes) == 0 {
+		scopes = []string{"openid", "email", "profile"}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return oidc.Discover(ctx, oidc.Config{
+		Issuer:       issuer,
+		ClientID:     os.Getenv("OIDC_CLIENT_ID"),
+		ClientSecret: os.Getenv("OIDC_CLIENT_SECRET"),
+		RedirectURL:  redirectURL,
+		Scopes:       scopes,
+	}, nil)
+}
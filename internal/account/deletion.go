@@ -0,0 +1,43 @@
+package account
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrDeletionUnconfirmed = errors.New("password or token is required to delete the account")
+
+// Deletion is a request to delete an account. It is confirmed with the
+// password, or with a mailed token for accounts that have no password to
+// give.
+type Deletion struct {
+	Password string
+	Token    string
+}
+
+// Confirmation says how a Deletion is confirmed.
+type Confirmation int
+
+const (
+	ConfirmWithPassword Confirmation = iota
+	ConfirmWithToken
+)
+
+// Confirmation picks how the deletion is confirmed. A token wins over a
+// password, since an account that asked for one may not have a password.
+func (d Deletion) Confirmation() (Confirmation, error) {
+	switch {
+	case d.Token != "":
+		return ConfirmWithToken, nil
+	case d.Password != "":
+		return ConfirmWithPassword, nil
+	default:
+		return 0, ErrDeletionUnconfirmed
+	}
+}
+
+// DeletionDue returns when an account whose deletion was asked for at now
+// is purged. Logging in before then cancels the deletion.
+func DeletionDue(now time.Time, gracePeriod time.Duration) time.Time {
+	return now.Add(gracePeriod)
+}
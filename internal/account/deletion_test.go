@@ -0,0 +1,44 @@
+package account
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestDeletion_Confirmation(t *testing.T) {
+	tests := []struct {
+		name     string
+		deletion Deletion
+		want     Confirmation
+		wantErr  error
+	}{
+		{name: "password", deletion: Deletion{Password: "hunter2"}, want: ConfirmWithPassword},
+		{name: "token", deletion: Deletion{Token: "abc"}, want: ConfirmWithToken},
+		{name: "token wins over password", deletion: Deletion{Password: "hunter2", Token: "abc"}, want: ConfirmWithToken},
+		{name: "neither", deletion: Deletion{}, wantErr: ErrDeletionUnconfirmed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.deletion.Confirmation()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if err == nil && got != tt.want {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDeletionDue(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	if got, want := DeletionDue(now, 30*24*time.Hour), time.Date(2025, 3, 31, 12, 0, 0, 0, time.UTC); !got.Equal(want) {
+		t.Fatalf("Expected %v, got %v", want, got)
+	}
+	if got := DeletionDue(now, 0); !got.Equal(now) {
+		t.Fatalf("Expected a zero grace period to be due right away, got %v", got)
+	}
+}
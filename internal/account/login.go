@@ -0,0 +1,37 @@
+package account
+
+import "errors"
+
+var (
+	ErrBanned           = errors.New("account is banned")
+	ErrEmailNotVerified = errors.New("email address is not verified")
+	ErrEmailChanged     = errors.New("the email address has changed since this link was sent")
+)
+
+// LoginState is what decides whether an account may get a session, whatever
+// the way it logs in.
+type LoginState struct {
+	Banned        bool
+	EmailVerified bool
+}
+
+// CheckLogin returns why an account may not log in, or nil if it may.
+func CheckLogin(state LoginState, requireVerifiedEmail bool) error {
+	if state.Banned {
+		return ErrBanned
+	}
+	if requireVerifiedEmail && !state.EmailVerified {
+		return ErrEmailNotVerified
+	}
+	return nil
+}
+
+// CheckMagicLink makes sure a magic link was mailed to the account's
+// current address. A link mailed before an email change doesn't prove
+// anything about the new address, and isn't good for a login either.
+func CheckMagicLink(currentEmail, sentTo string) error {
+	if currentEmail != sentTo {
+		return ErrEmailChanged
+	}
+	return nil
+}
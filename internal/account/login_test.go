@@ -0,0 +1,50 @@
+package account
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckLogin(t *testing.T) {
+	tests := []struct {
+		name            string
+		state           LoginState
+		requireVerified bool
+		want            error
+	}{
+		{name: "verified", state: LoginState{EmailVerified: true}, requireVerified: true},
+		{name: "unverified allowed", state: LoginState{}, requireVerified: false},
+		{name: "unverified refused", state: LoginState{}, requireVerified: true, want: ErrEmailNotVerified},
+		{name: "banned", state: LoginState{Banned: true, EmailVerified: true}, want: ErrBanned},
+		{name: "banned wins over unverified", state: LoginState{Banned: true}, requireVerified: true, want: ErrBanned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckLogin(tt.state, tt.requireVerified); !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCheckMagicLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		sentTo  string
+		want    error
+	}{
+		{name: "same address", current: "user@example.com", sentTo: "user@example.com"},
+		{name: "changed address", current: "new@example.com", sentTo: "user@example.com", want: ErrEmailChanged},
+		{name: "case differs", current: "User@example.com", sentTo: "user@example.com", want: ErrEmailChanged},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := CheckMagicLink(tt.current, tt.sentTo); !errors.Is(err, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// Package account holds the rules for what users can do with their own
+// account, such as logging in, changing it or deleting it, apart from the
+// HTTP handlers that apply them.
+package account
+
+import (
+	"errors"
+	"net/mail"
+)
+
+var (
+	ErrInvalidEmail            = errors.New("invalid email address")
+	ErrNothingToUpdate         = errors.New("nothing to update")
+	ErrCurrentPasswordRequired = errors.New("current_password is required to change the email address or password")
+)
+
+// ValidateEmail accepts a bare address such as "user@example.com".
+func ValidateEmail(email string) error {
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return ErrInvalidEmail
+	}
+	return nil
+}
+
+// Update is a change a user asks for. Nil fields are left as they are.
+type Update struct {
+	Email           *string
+	Password        *string
+	CurrentPassword string
+}
+
+// Plan is what an Update turns out to change.
+type Plan struct {
+	// Email is the address the account ends up with.
+	Email          string
+	EmailChanged   bool
+	ChangePassword bool
+}
+
+// Plan checks an update against the account's current email. Changing the
+// email or the password needs the current password, so a stolen access
+// token isn't enough to take over the account, be it directly or through a
+// password reset mailed to an address of the attacker's. Plan only checks
+// that it was given; the caller verifies it.
+func (u Update) Plan(currentEmail string) (Plan, error) {
+	if u.Email == nil && u.Password == nil {
+		return Plan{}, ErrNothingToUpdate
+	}
+
+	plan := Plan{Email: currentEmail, ChangePassword: u.Password != nil}
+	if u.Email != nil && *u.Email != currentEmail {
+		if err := ValidateEmail(*u.Email); err != nil {
+			return Plan{}, err
+		}
+		plan.Email = *u.Email
+		plan.EmailChanged = true
+	}
+
+	if (plan.EmailChanged || plan.ChangePassword) && u.CurrentPassword == "" {
+		return Plan{}, ErrCurrentPasswordRequired
+	}
+
+	return plan, nil
+}
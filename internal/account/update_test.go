@@ -0,0 +1,88 @@
+package account
+
+import (
+	"errors"
+	"testing"
+)
+
+func ptr(s string) *string {
+	return &s
+}
+
+func TestUpdate_Plan(t *testing.T) {
+	const current = "user@example.com"
+
+	tests := []struct {
+		name    string
+		update  Update
+		want    Plan
+		wantErr error
+	}{
+		{
+			name:    "empty",
+			update:  Update{CurrentPassword: "hunter2"},
+			wantErr: ErrNothingToUpdate,
+		},
+		{
+			name:    "new email without current password",
+			update:  Update{Email: ptr("new@example.com")},
+			wantErr: ErrCurrentPasswordRequired,
+		},
+		{
+			name:    "new password without current password",
+			update:  Update{Password: ptr("correct horse")},
+			wantErr: ErrCurrentPasswordRequired,
+		},
+		{
+			name:    "invalid email",
+			update:  Update{Email: ptr("Name <new@example.com>"), CurrentPassword: "hunter2"},
+			wantErr: ErrInvalidEmail,
+		},
+		{
+			name:   "same email needs no password",
+			update: Update{Email: ptr(current)},
+			want:   Plan{Email: current},
+		},
+		{
+			name:   "new email",
+			update: Update{Email: ptr("new@example.com"), CurrentPassword: "hunter2"},
+			want:   Plan{Email: "new@example.com", EmailChanged: true},
+		},
+		{
+			name:   "new password",
+			update: Update{Password: ptr("correct horse"), CurrentPassword: "hunter2"},
+			want:   Plan{Email: current, ChangePassword: true},
+		},
+		{
+			name:   "both",
+			update: Update{Email: ptr("new@example.com"), Password: ptr("correct horse"), CurrentPassword: "hunter2"},
+			want:   Plan{Email: "new@example.com", EmailChanged: true, ChangePassword: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.update.Plan(current)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Expected error %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Fatalf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestValidateEmail(t *testing.T) {
+	for email, valid := range map[string]bool{
+		"user@example.com":        true,
+		"user":                    false,
+		"":                        false,
+		"User <user@example.com>": false,
+		" user@example.com":       false,
+	} {
+		if err := ValidateEmail(email); (err == nil) != valid {
+			t.Errorf("ValidateEmail(%q) = %v, want valid %v", email, err, valid)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 package auth
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"strings"
@@ -20,3 +22,18 @@ func GetAPIKey(headers http.Header) (string, error) {
 
 	return parts[1], nil
 }
+
+// APIKeyPrefix marks personal API keys so they are easy to spot in config
+// files and secret scanners.
+const APIKeyPrefix = "chirpy_"
+
+// MakeAPIKey returns a new personal API key. Only its hash is stored.
+func MakeAPIKey() (string, error) {
+	randomBytes := make([]byte, 32)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return APIKeyPrefix + hex.EncodeToString(randomBytes), nil
+}
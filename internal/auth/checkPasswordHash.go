@@ -1,7 +1,6 @@
 package auth
 
-import "golang.org/x/crypto/bcrypt"
-
+// CheckPasswordHash verifies a password against an argon2id or bcrypt hash.
 func CheckPasswordHash(password, hash string) error {
-	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+	return defaultPasswordHasher.Check(password, hash)
 }
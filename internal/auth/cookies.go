@@ -0,0 +1,59 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net/http"
+)
+
+// Browser clients may keep their refresh token in an HttpOnly cookie instead
+// of JS-accessible storage. Requests authenticated by that cookie must also
+// echo the CSRF cookie in the CSRF header (double-submit): another site can
+// make the browser send our cookies, but it can't read them.
+const (
+	RefreshTokenCookie = "chirpy_refresh_token"
+	CSRFCookie         = "chirpy_csrf"
+	CSRFHeader         = "X-CSRF-Token"
+)
+
+var ErrCSRFMismatch = errors.New("csrf token is missing or doesn't match")
+
+// GetRefreshTokenCookie returns the refresh token stored in the cookie.
+func GetRefreshTokenCookie(r *http.Request) (string, error) {
+	cookie, err := r.Cookie(RefreshTokenCookie)
+	if err != nil {
+		return "", err
+	}
+	if cookie.Value == "" {
+		return "", errors.New("empty refresh token cookie")
+	}
+
+	return cookie.Value, nil
+}
+
+func MakeCSRFToken() (string, error) {
+	randomBytes := make([]byte, 32)
+	_, err := rand.Read(randomBytes)
+	if err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(randomBytes), nil
+}
+
+// CheckCSRF verifies that the CSRF header matches the CSRF cookie.
+func CheckCSRF(r *http.Request) error {
+	cookie, err := r.Cookie(CSRFCookie)
+	if err != nil || cookie.Value == "" {
+		return ErrCSRFMismatch
+	}
+
+	header := r.Header.Get(CSRFHeader)
+	if subtle.ConstantTimeCompare([]byte(header), []byte(cookie.Value)) != 1 {
+		return ErrCSRFMismatch
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckCSRF(t *testing.T) {
+	token, err := MakeCSRFToken()
+	if err != nil {
+		t.Fatalf("Failed to create csrf token: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		cookie string
+		header string
+		ok     bool
+	}{
+		{name: "matching", cookie: token, header: token, ok: true},
+		{name: "missing header", cookie: token},
+		{name: "missing cookie", header: token},
+		{name: "both empty"},
+		{name: "mismatch", cookie: token, header: token + "0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodPost, "/api/refresh", nil)
+			if tt.cookie != "" {
+				r.AddCookie(&http.Cookie{Name: CSRFCookie, Value: tt.cookie})
+			}
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+
+			err := CheckCSRF(r)
+			if tt.ok && err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrCSRFMismatch) {
+				t.Fatalf("Expected ErrCSRFMismatch, got %v", err)
+			}
+		})
+	}
+}
+
+func TestGetRefreshTokenCookie(t *testing.T) {
+	r := httptest.NewRequest(http.MethodPost, "/api/refresh", nil)
+	if _, err := GetRefreshTokenCookie(r); err == nil {
+		t.Fatal("Expected error without cookie, got none")
+	}
+
+	r.AddCookie(&http.Cookie{Name: RefreshTokenCookie, Value: "abc"})
+	token, err := GetRefreshTokenCookie(r)
+	if err != nil || token != "abc" {
+		t.Fatalf("Expected token abc, got %q (%v)", token, err)
+	}
+}
@@ -0,0 +1,75 @@
+package auth
+
+import (
+	"testing"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+var testPasswordParams = PasswordParams{
+	Memory:      1024,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func TestPasswordHasher_Argon2id(t *testing.T) {
+	h := NewPasswordHasher(testPasswordParams)
+
+	hash, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if err := h.Check("correct horse", hash); err != nil {
+		t.Fatalf("Expected password to match, got %v", err)
+	}
+
+	if err := h.Check("wrong horse", hash); err == nil {
+		t.Fatal("Expected error for wrong password, got none")
+	}
+
+	if h.NeedsRehash(hash) {
+		t.Fatal("Expected hash with current parameters not to need a rehash")
+	}
+
+	stronger := testPasswordParams
+	stronger.Iterations = 2
+	if !NewPasswordHasher(stronger).NeedsRehash(hash) {
+		t.Fatal("Expected hash with outdated parameters to need a rehash")
+	}
+
+	if err := NewPasswordHasher(stronger).Check("correct horse", hash); err != nil {
+		t.Fatalf("Expected outdated hash to still verify, got %v", err)
+	}
+}
+
+func TestPasswordHasher_Bcrypt(t *testing.T) {
+	h := NewPasswordHasher(testPasswordParams)
+
+	legacy, err := bcrypt.GenerateFromPassword([]byte("correct horse"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("Failed to create bcrypt hash: %v", err)
+	}
+
+	if err := h.Check("correct horse", string(legacy)); err != nil {
+		t.Fatalf("Expected bcrypt hash to verify, got %v", err)
+	}
+
+	if err := h.Check("wrong horse", string(legacy)); err == nil {
+		t.Fatal("Expected error for wrong password, got none")
+	}
+
+	if !h.NeedsRehash(string(legacy)) {
+		t.Fatal("Expected bcrypt hash to need a rehash")
+	}
+}
+
+func TestPasswordHasher_UnknownFormat(t *testing.T) {
+	h := NewPasswordHasher(testPasswordParams)
+
+	if err := h.Check("unset", "unset"); err == nil {
+		t.Fatal("Expected error for unknown hash format, got none")
+	}
+}
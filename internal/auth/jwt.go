@@ -12,44 +12,57 @@ import (
 	"github.com/google/uuid"
 )
 
-type MyCustomClaims struct {
+type Claims struct {
 	jwt.RegisteredClaims
+	Roles []string `json:"roles,omitempty"`
+	// SessionID is the refresh token family the token was issued for.
+	SessionID string `json:"sid,omitempty"`
 }
 
-func MakeJWT(userID uuid.UUID, tokenSecret string) (string, error) {
-	j := jwt.NewWithClaims(jwt.SigningMethodHS256,
-		jwt.MapClaims{
-			"iss": "chirpy",
-			"iat": time.Now().Unix(),
-			"exp": time.Now().Add(time.Hour).Unix(),
-			"sub": userID.String(),
-		})
-
-	return j.SignedString([]byte(tokenSecret))
-}
-
-func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
-	claims := jwt.MapClaims{}
-
-	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (any, error) {
-		return []byte(tokenSecret), nil
-	})
-
-	if err != nil || !token.Valid {
-		return uuid.Nil, err
+// UserID returns the subject of the token as a user id.
+func (c *Claims) UserID() (uuid.UUID, error) {
+	if c.Subject == "" {
+		return uuid.Nil, errors.New("invalid subject claim")
 	}
+	return uuid.Parse(c.Subject)
+}
 
-	subject, ok := claims["sub"].(string)
-	if !ok {
-		return uuid.Nil, errors.New("invalid subject claim")
+// TokenID returns the jti claim, or uuid.Nil for tokens issued without one.
+func (c *Claims) TokenID() uuid.UUID {
+	id, err := uuid.Parse(c.ID)
+	if err != nil {
+		return uuid.Nil
 	}
+	return id
+}
 
-	userID, err := uuid.Parse(subject)
+// Session returns the sid claim, or uuid.Nil for tokens issued without one.
+func (c *Claims) Session() uuid.UUID {
+	id, err := uuid.Parse(c.SessionID)
 	if err != nil {
-		return uuid.Nil, err
+		return uuid.Nil
 	}
+	return id
+}
+
+type TokenParams struct {
+	UserID    uuid.UUID
+	SessionID uuid.UUID
+	Roles     []string
+	ExpiresIn time.Duration
+}
 
-	return userID, nil
+// MakeJWT signs an HS256 access token with a shared secret.
+func MakeJWT(userID uuid.UUID, tokenSecret string, expiresIn time.Duration) (string, error) {
+	return NewHMACKeySet(tokenSecret).MakeJWT(TokenParams{
+		UserID:    userID,
+		ExpiresIn: expiresIn,
+	})
+}
+
+// ValidateJWT checks an HS256 access token signed with a shared secret.
+func ValidateJWT(tokenString, tokenSecret string) (uuid.UUID, error) {
+	return NewHMACKeySet(tokenSecret).ValidateJWT(tokenString)
 }
 
 func GetBearerToken(headers http.Header) (string, error) {
@@ -0,0 +1,300 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+// SigningKey is one entry of a KeySet. Keys without a private part can only
+// verify tokens, which is how retired keys are kept around until every token
+// they signed has expired.
+type SigningKey struct {
+	ID      string
+	Method  jwt.SigningMethod
+	private crypto.Signer
+	public  crypto.PublicKey
+}
+
+// KeySet signs access tokens with its active key and verifies them with any
+// key it knows about, picked by the kid header of the token.
+type KeySet struct {
+	active      *SigningKey
+	keys        map[string]*SigningKey
+	hmacSecret  []byte
+	hmacUntil   time.Time
+	revocations Revocations
+}
+
+// Revocations tells whether an access token has been revoked before it
+// expired. It is consulted on every validation, so implementations should
+// answer from memory.
+type Revocations interface {
+	IsRevoked(userID uuid.UUID, issuedAt time.Time, tokenID, sessionID uuid.UUID) bool
+}
+
+var ErrTokenRevoked = errors.New("token has been revoked")
+
+// NewHMACKeySet returns a key set that signs and verifies with a shared
+// HS256 secret. It is meant for local development; it publishes no keys.
+func NewHMACKeySet(secret string) *KeySet {
+	return &KeySet{
+		keys:       map[string]*SigningKey{},
+		hmacSecret: []byte(secret),
+	}
+}
+
+// LoadKeySet reads every *.pem file in dir. The file name without extension
+// becomes the kid of the key. Files may hold a PKCS#8 or PKCS#1 private key
+// (RSA or Ed25519), or a PKIX public key for verify-only keys.
+func LoadKeySet(dir, activeKID string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	ks := &KeySet{keys: map[string]*SigningKey{}}
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		kid := strings.TrimSuffix(entry.Name(), ".pem")
+		key, err := parseSigningKey(kid, data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load key %s: %w", kid, err)
+		}
+		ks.keys[kid] = key
+	}
+
+	if activeKID == "" {
+		var signers []string
+		for kid, key := range ks.keys {
+			if key.private != nil {
+				signers = append(signers, kid)
+			}
+		}
+		if len(signers) != 1 {
+			return nil, errors.New("an active key id is required when there isn't exactly one private key")
+		}
+		activeKID = signers[0]
+	}
+
+	active, ok := ks.keys[activeKID]
+	if !ok {
+		return nil, fmt.Errorf("active key %s not found in %s", activeKID, dir)
+	}
+	if active.private == nil {
+		return nil, fmt.Errorf("active key %s has no private key", activeKID)
+	}
+	ks.active = active
+
+	return ks, nil
+}
+
+// WithHMACFallback makes the key set accept HS256 tokens signed with secret
+// until the given time, so tokens issued before switching to asymmetric keys
+// stay valid while they run out.
+func (ks *KeySet) WithHMACFallback(secret string, until time.Time) *KeySet {
+	if secret != "" {
+		ks.hmacSecret = []byte(secret)
+		ks.hmacUntil = until
+	}
+	return ks
+}
+
+// WithRevocations makes the key set reject tokens that were revoked.
+func (ks *KeySet) WithRevocations(revocations Revocations) *KeySet {
+	ks.revocations = revocations
+	return ks
+}
+
+func parseSigningKey(kid string, data []byte) (*SigningKey, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM block found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	key := &SigningKey{ID: kid}
+	switch k := parsed.(type) {
+	case *rsa.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodRS256, k, &k.PublicKey
+	case ed25519.PrivateKey:
+		key.Method, key.private, key.public = jwt.SigningMethodEdDSA, k, k.Public()
+	case *rsa.PublicKey:
+		key.Method, key.public = jwt.SigningMethodRS256, k
+	case ed25519.PublicKey:
+		key.Method, key.public = jwt.SigningMethodEdDSA, k
+	default:
+		return nil, fmt.Errorf("unsupported key type %T", parsed)
+	}
+
+	return key, nil
+}
+
+func (ks *KeySet) MakeJWT(params TokenParams) (string, error) {
+	now := time.Now()
+	claims := Claims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    "chirpy",
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(params.ExpiresIn)),
+			Subject:   params.UserID.String(),
+			ID:        uuid.NewString(),
+		},
+		Roles: params.Roles,
+	}
+	if params.SessionID != uuid.Nil {
+		claims.SessionID = params.SessionID.String()
+	}
+
+	if ks.active == nil {
+		if ks.hmacSecret == nil {
+			return "", errors.New("key set has no signing key")
+		}
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.hmacSecret)
+	}
+
+	j := jwt.NewWithClaims(ks.active.Method, claims)
+	j.Header["kid"] = ks.active.ID
+	return j.SignedString(ks.active.private)
+}
+
+// ParseJWT verifies an access token and returns its claims.
+func (ks *KeySet) ParseJWT(tokenString string) (*Claims, error) {
+	claims := &Claims{}
+
+	token, err := jwt.ParseWithClaims(tokenString, claims, ks.keyFunc,
+		jwt.WithValidMethods([]string{"RS256", "EdDSA", "HS256"}))
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("invalid token")
+	}
+
+	if ks.revocations != nil {
+		userID, err := claims.UserID()
+		if err != nil {
+			return nil, err
+		}
+
+		var issuedAt time.Time
+		if claims.IssuedAt != nil {
+			issuedAt = claims.IssuedAt.Time
+		}
+		if ks.revocations.IsRevoked(userID, issuedAt, claims.TokenID(), claims.Session()) {
+			return nil, ErrTokenRevoked
+		}
+	}
+
+	return claims, nil
+}
+
+func (ks *KeySet) ValidateJWT(tokenString string) (uuid.UUID, error) {
+	claims, err := ks.ParseJWT(tokenString)
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	return claims.UserID()
+}
+
+func (ks *KeySet) keyFunc(token *jwt.Token) (any, error) {
+	if token.Method.Alg() == jwt.SigningMethodHS256.Alg() {
+		if ks.hmacSecret == nil {
+			return nil, errors.New("hmac signed tokens are not accepted")
+		}
+		if !ks.hmacUntil.IsZero() && time.Now().After(ks.hmacUntil) {
+			return nil, errors.New("hmac signed tokens are no longer accepted")
+		}
+		return ks.hmacSecret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key id %q", kid)
+	}
+	if key.Method.Alg() != token.Method.Alg() {
+		return nil, fmt.Errorf("key %s does not sign with %s", kid, token.Method.Alg())
+	}
+
+	return key.public, nil
+}
+
+// JWK is the public part of a signing key in RFC 7517 form.
+type JWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	Alg string `json:"alg"`
+	N   string `json:"n,omitempty"`
+	E   string `json:"e,omitempty"`
+	Crv string `json:"crv,omitempty"`
+	X   string `json:"x,omitempty"`
+}
+
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, retired ones included, so other
+// services can verify every token that hasn't expired yet.
+func (ks *KeySet) JWKS() JWKS {
+	result := JWKS{Keys: []JWK{}}
+	for _, key := range ks.keys {
+		jwk := JWK{Kid: key.ID, Use: "sig", Alg: key.Method.Alg()}
+		switch pub := key.public.(type) {
+		case *rsa.PublicKey:
+			jwk.Kty = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.Kty = "OKP"
+			jwk.Crv = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+		}
+		result.Keys = append(result.Keys, jwk)
+	}
+
+	sort.Slice(result.Keys, func(i, j int) bool {
+		return result.Keys[i].Kid < result.Keys[j].Kid
+	})
+	return result
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func writeRSAKey(t *testing.T, dir, kid string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate rsa key: %v", err)
+	}
+	writePEM(t, dir, kid, "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(key))
+}
+
+func writeEd25519Key(t *testing.T, dir, kid string) {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatalf("Failed to generate ed25519 key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatalf("Failed to marshal ed25519 key: %v", err)
+	}
+	writePEM(t, dir, kid, "PRIVATE KEY", der)
+}
+
+func writePEM(t *testing.T, dir, kid, blockType string, der []byte) {
+	t.Helper()
+	data := pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der})
+	if err := os.WriteFile(filepath.Join(dir, kid+".pem"), data, 0o600); err != nil {
+		t.Fatalf("Failed to write key: %v", err)
+	}
+}
+
+func TestKeySet_SignAndValidate(t *testing.T) {
+	for _, alg := range []string{"RS256", "EdDSA"} {
+		t.Run(alg, func(t *testing.T) {
+			dir := t.TempDir()
+			if alg == "RS256" {
+				writeRSAKey(t, dir, "k1")
+			} else {
+				writeEd25519Key(t, dir, "k1")
+			}
+
+			ks, err := LoadKeySet(dir, "")
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			userID := uuid.New()
+			token, err := ks.MakeJWT(TokenParams{UserID: userID, ExpiresIn: time.Hour})
+			if err != nil {
+				t.Fatalf("Failed to create token: %v", err)
+			}
+
+			validatedUserID, err := ks.ValidateJWT(token)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if validatedUserID != userID {
+				t.Fatalf("Expected userID %v, got %v", userID, validatedUserID)
+			}
+
+			jwks := ks.JWKS()
+			if len(jwks.Keys) != 1 || jwks.Keys[0].Kid != "k1" || jwks.Keys[0].Alg != alg {
+				t.Fatalf("Unexpected jwks: %+v", jwks)
+			}
+		})
+	}
+}
+
+func TestKeySet_Rotation(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "old")
+
+	oldSet, err := LoadKeySet(dir, "old")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	userID := uuid.New()
+	token, err := oldSet.MakeJWT(TokenParams{UserID: userID, ExpiresIn: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	writeEd25519Key(t, dir, "new")
+	newSet, err := LoadKeySet(dir, "new")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := newSet.ValidateJWT(token); err != nil {
+		t.Fatalf("Expected token signed by the retired key to validate, got %v", err)
+	}
+
+	if len(newSet.JWKS().Keys) != 2 {
+		t.Fatalf("Expected both keys to be published, got %+v", newSet.JWKS())
+	}
+}
+
+func TestKeySet_UnknownKeyID(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "k1")
+	ks, err := LoadKeySet(dir, "k1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	other := t.TempDir()
+	writeRSAKey(t, other, "k2")
+	otherSet, err := LoadKeySet(other, "k2")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	token, err := otherSet.MakeJWT(TokenParams{UserID: uuid.New(), ExpiresIn: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	if _, err := ks.ValidateJWT(token); err == nil {
+		t.Fatal("Expected error for unknown key id, got none")
+	}
+}
+
+func TestKeySet_RejectsHMACWithoutFallback(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "k1")
+	ks, err := LoadKeySet(dir, "k1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	token, err := MakeJWT(uuid.New(), "test-secret", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	if _, err := ks.ValidateJWT(token); err == nil {
+		t.Fatal("Expected error for hmac token, got none")
+	}
+
+	if _, err := ks.WithHMACFallback("test-secret", time.Now().Add(time.Hour)).ValidateJWT(token); err != nil {
+		t.Fatalf("Expected hmac token to validate with fallback, got %v", err)
+	}
+}
+
+func TestKeySet_HMACFallbackEnds(t *testing.T) {
+	dir := t.TempDir()
+	writeRSAKey(t, dir, "k1")
+	ks, err := LoadKeySet(dir, "k1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	token, err := MakeJWT(uuid.New(), "test-secret", time.Hour)
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	if _, err := ks.WithHMACFallback("test-secret", time.Now().Add(-time.Second)).ValidateJWT(token); err == nil {
+		t.Fatal("Expected error for hmac token after the fallback ended, got none")
+	}
+}
+
+type revokedSessions map[uuid.UUID]bool
+
+func (r revokedSessions) IsRevoked(userID uuid.UUID, issuedAt time.Time, tokenID, sessionID uuid.UUID) bool {
+	return r[sessionID]
+}
+
+func TestKeySet_Revocations(t *testing.T) {
+	revoked := revokedSessions{}
+	ks := NewHMACKeySet("test-secret").WithRevocations(revoked)
+
+	sessionID := uuid.New()
+	token, err := ks.MakeJWT(TokenParams{UserID: uuid.New(), SessionID: sessionID, ExpiresIn: time.Hour})
+	if err != nil {
+		t.Fatalf("Failed to create token: %v", err)
+	}
+
+	claims, err := ks.ParseJWT(token)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if claims.TokenID() == uuid.Nil || claims.Session() != sessionID {
+		t.Fatalf("Expected jti and sid claims, got %+v", claims)
+	}
+
+	revoked[sessionID] = true
+	if _, err := ks.ValidateJWT(token); !errors.Is(err, ErrTokenRevoked) {
+		t.Fatalf("Expected ErrTokenRevoked, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package auth
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+)
+
+// Names of the password policy rules, as reported to API clients.
+const (
+	RuleMinLength      = "min_length"
+	RuleMaxLength      = "max_length"
+	RuleBanned         = "banned"
+	RuleSimilarToEmail = "similar_to_email"
+	RuleBreached       = "breached"
+)
+
+// PolicyViolation names the rule a password broke.
+type PolicyViolation struct {
+	Rule    string
+	Message string
+}
+
+func (v *PolicyViolation) Error() string {
+	return v.Message
+}
+
+// BreachedPasswords tells whether a password is known from a data breach.
+type BreachedPasswords interface {
+	IsBreached(password string) (bool, error)
+}
+
+type PasswordPolicy struct {
+	MinLength int
+	// MaxLength of zero means no limit.
+	MaxLength int
+	// Banned holds lowercased passwords that are refused outright.
+	Banned   map[string]struct{}
+	Breached BreachedPasswords
+}
+
+// commonPasswords are refused even without a configured banned list.
+var commonPasswords = []string{
+	"password", "password1", "123456", "12345678", "123456789", "1234567890",
+	"qwerty", "qwertyuiop", "letmein", "iloveyou", "admin", "welcome", "chirpy",
+}
+
+func NewPasswordPolicy(minLength, maxLength int) *PasswordPolicy {
+	p := &PasswordPolicy{
+		MinLength: minLength,
+		MaxLength: maxLength,
+		Banned:    map[string]struct{}{},
+	}
+	for _, password := range commonPasswords {
+		p.Banned[password] = struct{}{}
+	}
+	return p
+}
+
+// LoadBannedPasswords adds every non-empty line of the file at path to the
+// banned list.
+func (p *PasswordPolicy) LoadBannedPasswords(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			p.Banned[strings.ToLower(line)] = struct{}{}
+		}
+	}
+	return scanner.Err()
+}
+
+// Check returns a *PolicyViolation for the first rule the password breaks.
+// Any other error means the check itself failed.
+func (p *PasswordPolicy) Check(password, email string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.MinLength {
+		return &PolicyViolation{
+			Rule:    RuleMinLength,
+			Message: fmt.Sprintf("Password must be at least %d characters long", p.MinLength),
+		}
+	}
+
+	if p.MaxLength > 0 && length > p.MaxLength {
+		return &PolicyViolation{
+			Rule:    RuleMaxLength,
+			Message: fmt.Sprintf("Password must be at most %d characters long", p.MaxLength),
+		}
+	}
+
+	lowered := strings.ToLower(password)
+	if _, ok := p.Banned[lowered]; ok {
+		return &PolicyViolation{
+			Rule:    RuleBanned,
+			Message: "Password is too common",
+		}
+	}
+
+	if similarToEmail(lowered, strings.ToLower(email)) {
+		return &PolicyViolation{
+			Rule:    RuleSimilarToEmail,
+			Message: "Password must not contain your email address",
+		}
+	}
+
+	if p.Breached != nil {
+		breached, err := p.Breached.IsBreached(password)
+		if err != nil {
+			return err
+		}
+		if breached {
+			return &PolicyViolation{
+				Rule:    RuleBreached,
+				Message: "Password has appeared in a data breach",
+			}
+		}
+	}
+
+	return nil
+}
+
+// similarToEmail catches passwords built from the user's address, such as
+// "jane.doe2024" for jane.doe@example.com.
+func similarToEmail(password, email string) bool {
+	if email == "" {
+		return false
+	}
+
+	local, _, _ := strings.Cut(email, "@")
+	if strings.Contains(password, email) {
+		return true
+	}
+	return len(local) >= 3 && strings.Contains(password, local)
+}
+
+// HIBPDirectory checks passwords against a local copy of the Have I Been
+// Pwned range API: one file per 5-character SHA-1 prefix, named after the
+// prefix (optionally with .txt), holding "SUFFIX:COUNT" lines.
+type HIBPDirectory struct {
+	Dir string
+}
+
+func (d HIBPDirectory) IsBreached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := digest[:5], digest[5:]
+
+	f, err := os.Open(filepath.Join(d.Dir, prefix))
+	if errors.Is(err, os.ErrNotExist) {
+		f, err = os.Open(filepath.Join(d.Dir, prefix+".txt"))
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		hash, count, _ := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		// Padded range files contain entries with a count of zero.
+		if strings.EqualFold(hash, suffix) && count != "0" {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPasswordPolicy_Rules(t *testing.T) {
+	dir := t.TempDir()
+	sum := sha1.Sum([]byte("hunter2hunter2"))
+	digest := strings.ToUpper(hex.EncodeToString(sum[:]))
+	content := "0000000000000000000000000000000000A:0\n" + digest[5:] + ":42\n"
+	if err := os.WriteFile(filepath.Join(dir, digest[:5]), []byte(content), 0o600); err != nil {
+		t.Fatalf("Failed to write range file: %v", err)
+	}
+
+	p := NewPasswordPolicy(8, 64)
+	p.Breached = HIBPDirectory{Dir: dir}
+
+	tests := []struct {
+		password string
+		rule     string
+	}{
+		{"", RuleMinLength},
+		{"short", RuleMinLength},
+		{strings.Repeat("a", 65), RuleMaxLength},
+		{"Password1", RuleBanned},
+		{"jane.doe2024!", RuleSimilarToEmail},
+		{"hunter2hunter2", RuleBreached},
+		{"plenty-of-entropy", ""},
+	}
+
+	for _, tt := range tests {
+		err := p.Check(tt.password, "jane.doe@example.com")
+		if tt.rule == "" {
+			if err != nil {
+				t.Fatalf("Expected %q to pass, got %v", tt.password, err)
+			}
+			continue
+		}
+
+		var violation *PolicyViolation
+		if !errors.As(err, &violation) {
+			t.Fatalf("Expected %q to violate %s, got %v", tt.password, tt.rule, err)
+		}
+		if violation.Rule != tt.rule {
+			t.Fatalf("Expected %q to violate %s, got %s", tt.password, tt.rule, violation.Rule)
+		}
+	}
+}
+
+func TestPasswordPolicy_CountsRunes(t *testing.T) {
+	p := NewPasswordPolicy(8, 0)
+	if err := p.Check("пароль-ок", ""); err != nil {
+		t.Fatalf("Expected 9 runes to pass, got %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+package auth
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// Method is how a request proved who it acts for.
+type Method string
+
+const (
+	MethodAccessToken Method = "access_token"
+	MethodAPIKey      Method = "api_key"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID uuid.UUID
+	// Roles come from the access token; API keys carry none.
+	Roles  []string
+	Method Method
+	// Scopes limit what an API key may do. Access tokens act for the user in
+	// full and have no scopes.
+	Scopes []string
+	// SessionID is the session of an access token, or uuid.Nil.
+	SessionID uuid.UUID
+}
+
+func (p *Principal) HasRole(role Role) bool {
+	return HasRole(p.Roles, role)
+}
+
+func (p *Principal) HasScope(scope Scope) bool {
+	if p.Method == MethodAccessToken {
+		return true
+	}
+	return HasScope(p.Scopes, scope)
+}
+
+type principalKey struct{}
+
+func ContextWithPrincipal(ctx context.Context, p *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, p)
+}
+
+// PrincipalFromContext returns the caller of the request, if it was
+// authenticated.
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok && p != nil
+}
+
+// MustPrincipal is PrincipalFromContext for handlers behind a middleware that
+// requires authentication. A missing principal is a routing bug.
+func MustPrincipal(ctx context.Context) *Principal {
+	p, ok := PrincipalFromContext(ctx)
+	if !ok {
+		panic("auth: no principal in context; is the route missing its authentication middleware?")
+	}
+	return p
+}
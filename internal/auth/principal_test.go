@@ -0,0 +1,40 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestPrincipalContext(t *testing.T) {
+	if _, ok := PrincipalFromContext(context.Background()); ok {
+		t.Fatal("Expected no principal in an empty context")
+	}
+
+	p := &Principal{UserID: uuid.New(), Method: MethodAccessToken}
+	ctx := ContextWithPrincipal(context.Background(), p)
+
+	got, ok := PrincipalFromContext(ctx)
+	if !ok || got.UserID != p.UserID {
+		t.Fatalf("Expected principal %v, got %v", p, got)
+	}
+	if MustPrincipal(ctx) != p {
+		t.Fatal("Expected MustPrincipal to return the stored principal")
+	}
+}
+
+func TestPrincipal_HasScope(t *testing.T) {
+	token := &Principal{Method: MethodAccessToken}
+	if !token.HasScope(ScopeChirpsWrite) {
+		t.Fatal("Expected access tokens to hold every scope")
+	}
+
+	key := &Principal{Method: MethodAPIKey, Scopes: []string{"chirps:read"}}
+	if !key.HasScope(ScopeChirpsRead) || key.HasScope(ScopeChirpsWrite) {
+		t.Fatalf("Expected api key to hold only its scopes, got %v", key.Scopes)
+	}
+	if key.HasRole(RoleAdmin) {
+		t.Fatal("Expected api keys to hold no roles")
+	}
+}
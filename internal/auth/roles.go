@@ -0,0 +1,43 @@
+package auth
+
+import "fmt"
+
+type Role string
+
+const (
+	RoleUser      Role = "user"
+	RoleModerator Role = "moderator"
+	RoleAdmin     Role = "admin"
+)
+
+// Roles are ordered: a moderator can do everything a user can, and an admin
+// everything a moderator can.
+var roleRanks = map[Role]int{
+	RoleUser:      0,
+	RoleModerator: 1,
+	RoleAdmin:     2,
+}
+
+func ParseRole(s string) (Role, error) {
+	role := Role(s)
+	if _, ok := roleRanks[role]; !ok {
+		return "", fmt.Errorf("unknown role %q", s)
+	}
+	return role, nil
+}
+
+// HasRole reports whether any of the granted roles is at least as privileged
+// as the required one. Every authenticated user implicitly holds RoleUser.
+func HasRole(granted []string, required Role) bool {
+	if required == RoleUser {
+		return true
+	}
+
+	for _, g := range granted {
+		rank, ok := roleRanks[Role(g)]
+		if ok && rank >= roleRanks[required] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,24 @@
+package auth
+
+import "testing"
+
+func TestHasRole(t *testing.T) {
+	tests := []struct {
+		granted  []string
+		required Role
+		want     bool
+	}{
+		{nil, RoleUser, true},
+		{nil, RoleModerator, false},
+		{[]string{"moderator"}, RoleModerator, true},
+		{[]string{"moderator"}, RoleAdmin, false},
+		{[]string{"admin"}, RoleModerator, true},
+		{[]string{"bogus"}, RoleModerator, false},
+	}
+
+	for _, tt := range tests {
+		if got := HasRole(tt.granted, tt.required); got != tt.want {
+			t.Fatalf("HasRole(%v, %s) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package auth
+
+import "fmt"
+
+type Scope string
+
+const (
+	ScopeChirpsRead  Scope = "chirps:read"
+	ScopeChirpsWrite Scope = "chirps:write"
+)
+
+var knownScopes = map[Scope]bool{
+	ScopeChirpsRead:  true,
+	ScopeChirpsWrite: true,
+}
+
+func ParseScope(s string) (Scope, error) {
+	scope := Scope(s)
+	if !knownScopes[scope] {
+		return "", fmt.Errorf("unknown scope %q", s)
+	}
+	return scope, nil
+}
+
+// HasScope reports whether the required scope was granted. Write access
+// implies read access to the same resource.
+func HasScope(granted []string, required Scope) bool {
+	for _, g := range granted {
+		if Scope(g) == required {
+			return true
+		}
+		if required == ScopeChirpsRead && Scope(g) == ScopeChirpsWrite {
+			return true
+		}
+	}
+	return false
+}
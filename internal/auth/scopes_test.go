@@ -0,0 +1,32 @@
+package auth
+
+import "testing"
+
+func TestParseScope(t *testing.T) {
+	if _, err := ParseScope("chirps:write"); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := ParseScope("chirps:admin"); err == nil {
+		t.Fatal("Expected error for unknown scope, got none")
+	}
+}
+
+func TestHasScope(t *testing.T) {
+	tests := []struct {
+		granted  []string
+		required Scope
+		want     bool
+	}{
+		{nil, ScopeChirpsRead, false},
+		{[]string{"chirps:read"}, ScopeChirpsRead, true},
+		{[]string{"chirps:read"}, ScopeChirpsWrite, false},
+		{[]string{"chirps:write"}, ScopeChirpsRead, true},
+		{[]string{"chirps:write"}, ScopeChirpsWrite, true},
+	}
+
+	for _, tt := range tests {
+		if got := HasScope(tt.granted, tt.required); got != tt.want {
+			t.Errorf("HasScope(%v, %s) = %v, want %v", tt.granted, tt.required, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+// Secret and expected values from RFC 6238, Appendix B (SHA-1), truncated to
+// six digits.
+const rfcSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+func TestTOTPCode_RFCVectors(t *testing.T) {
+	tests := []struct {
+		unix int64
+		want string
+	}{
+		{59, "287082"},
+		{1111111109, "081804"},
+		{1111111111, "050471"},
+		{1234567890, "005924"},
+		{2000000000, "279037"},
+	}
+
+	for _, tt := range tests {
+		got, err := TOTPCode(rfcSecret, time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if got != tt.want {
+			t.Fatalf("At %d expected %s, got %s", tt.unix, tt.want, got)
+		}
+	}
+}
+
+func TestValidateTOTP_Skew(t *testing.T) {
+	now := time.Unix(1111111109, 0)
+	code, err := TOTPCode(rfcSecret, now.Add(-30*time.Second))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	step, ok := ValidateTOTP(rfcSecret, code, now)
+	if !ok {
+		t.Fatal("Expected code from the previous period to validate")
+	}
+	if step != now.Unix()/30-1 {
+		t.Fatalf("Expected step %d, got %d", now.Unix()/30-1, step)
+	}
+
+	if _, ok := ValidateTOTP(rfcSecret, code, now.Add(2*time.Minute)); ok {
+		t.Fatal("Expected stale code to be rejected")
+	}
+}
+
+func TestTOTPURI(t *testing.T) {
+	uri := TOTPURI(rfcSecret, "Chirpy", "user@example.com")
+	if !strings.HasPrefix(uri, "otpauth://totp/Chirpy:user@example.com?") {
+		t.Fatalf("Unexpected uri %s", uri)
+	}
+	if !strings.Contains(uri, "secret="+rfcSecret) {
+		t.Fatalf("Expected uri to contain the secret, got %s", uri)
+	}
+}
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	seen := map[string]bool{}
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("Unexpected code format %q", code)
+		}
+		if seen[code] {
+			t.Fatalf("Duplicate code %q", code)
+		}
+		seen[code] = true
+
+		if NormalizeRecoveryCode(strings.ToUpper(code)) != strings.ReplaceAll(code, "-", "") {
+			t.Fatalf("Expected %q to normalize", code)
+		}
+	}
+}
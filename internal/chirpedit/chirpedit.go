@@ -0,0 +1,78 @@
+// Package chirpedit holds the rules for editing chirps after they are
+// posted, apart from the HTTP handlers that apply them.
+package chirpedit
+
+import (
+	"cmp"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EditableAfter returns the creation time a chirp has to be newer than to
+// still be editable at now.
+func EditableAfter(now time.Time, window time.Duration) time.Time {
+	return now.Add(-window)
+}
+
+// InWindow reports whether a chirp created at createdAt can still be edited
+// at now. A chirp exactly window old is no longer editable, the same as the
+// database's created_at > editable_after.
+func InWindow(createdAt, now time.Time, window time.Duration) bool {
+	return createdAt.After(EditableAfter(now, window))
+}
+
+// Version is a chirp's body and moderation status.
+type Version struct {
+	Body   string
+	Status string
+}
+
+// Outcome says what to do with a requested edit.
+type Outcome int
+
+const (
+	// Apply means the chirp is updated and the old body kept as a revision.
+	Apply Outcome = iota
+	// Unchanged means the edit would not change anything.
+	Unchanged
+	// WindowClosed means the chirp is too old to be edited.
+	WindowClosed
+)
+
+// Check decides what happens to an edit of a chirp created at createdAt.
+// The window comes first, so an edit that changes nothing still tells the
+// author that the chirp can no longer be edited.
+func Check(createdAt time.Time, current, edited Version, now time.Time, window time.Duration) Outcome {
+	if !InWindow(createdAt, now, window) {
+		return WindowClosed
+	}
+	if edited == current {
+		return Unchanged
+	}
+	return Apply
+}
+
+// Revision is an earlier body of a chirp. CreatedAt is when that body was
+// written and ReplacedAt when an edit replaced it.
+type Revision struct {
+	ID         uuid.UUID
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+// SortNewestFirst orders revisions most recently replaced first. Edits
+// replaced within the same microsecond are ordered by when their body was
+// written, and by ID after that, so the order never changes between
+// requests.
+func SortNewestFirst(revisions []Revision) {
+	slices.SortFunc(revisions, func(a, b Revision) int {
+		return cmp.Or(
+			b.ReplacedAt.Compare(a.ReplacedAt),
+			b.CreatedAt.Compare(a.CreatedAt),
+			cmp.Compare(a.ID.String(), b.ID.String()),
+		)
+	})
+}
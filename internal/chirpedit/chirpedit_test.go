@@ -0,0 +1,84 @@
+package chirpedit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestInWindow(t *testing.T) {
+	const window = 15 * time.Minute
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		want      bool
+	}{
+		{"just posted", now, true},
+		{"one microsecond before the window ends", now.Add(-window + time.Microsecond), true},
+		{"exactly at the end of the window", now.Add(-window), false},
+		{"after the window", now.Add(-window - time.Second), false},
+		{"posted in the future", now.Add(time.Minute), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := InWindow(tt.createdAt, now, window); got != tt.want {
+				t.Errorf("InWindow() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	const window = 15 * time.Minute
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	current := Version{Body: "hello", Status: "published"}
+
+	tests := []struct {
+		name      string
+		createdAt time.Time
+		edited    Version
+		want      Outcome
+	}{
+		{"new body", now.Add(-time.Minute), Version{Body: "hello world", Status: "published"}, Apply},
+		{"new status", now.Add(-time.Minute), Version{Body: "hello", Status: "held"}, Apply},
+		{"same body and status", now.Add(-time.Minute), current, Unchanged},
+		{"new body after the window", now.Add(-window), Version{Body: "hello world", Status: "published"}, WindowClosed},
+		{"same body after the window", now.Add(-window), current, WindowClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Check(tt.createdAt, current, tt.edited, now, window); got != tt.want {
+				t.Errorf("Check() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSortNewestFirst(t *testing.T) {
+	base := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	first := uuid.MustParse("00000000-0000-0000-0000-000000000001")
+	second := uuid.MustParse("00000000-0000-0000-0000-000000000002")
+	third := uuid.MustParse("00000000-0000-0000-0000-000000000003")
+	fourth := uuid.MustParse("00000000-0000-0000-0000-000000000004")
+
+	revisions := []Revision{
+		{ID: first, CreatedAt: base, ReplacedAt: base.Add(time.Minute)},
+		{ID: fourth, CreatedAt: base.Add(2 * time.Minute), ReplacedAt: base.Add(2 * time.Minute)},
+		{ID: third, CreatedAt: base.Add(2 * time.Minute), ReplacedAt: base.Add(2 * time.Minute)},
+		{ID: second, CreatedAt: base.Add(time.Minute), ReplacedAt: base.Add(2 * time.Minute)},
+	}
+
+	SortNewestFirst(revisions)
+
+	want := []uuid.UUID{third, fourth, second, first}
+	for i, revision := range revisions {
+		if revision.ID != want[i] {
+			t.Errorf("revision %d = %s, want %s", i, revision.ID, want[i])
+		}
+	}
+}
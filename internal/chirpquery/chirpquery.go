@@ -0,0 +1,126 @@
+// Package chirpquery reads the query parameters of chirp listings, apart
+// from the HTTP handlers that run them.
+package chirpquery
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// Page sizes of chirp listings.
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// MaxAuthors caps how many authors one listing can filter by.
+const MaxAuthors = 50
+
+// ParamError is a query parameter that can't be used. Message is meant for
+// the client.
+type ParamError struct {
+	Param   string
+	Message string
+}
+
+func (e *ParamError) Error() string {
+	return e.Param + ": " + e.Message
+}
+
+// Listing is what GET /api/chirps was asked for. Zero Since and Until mean
+// no bound; a nil After means the first page.
+type Listing struct {
+	AuthorIDs []uuid.UUID
+	Desc      bool
+	Since     time.Time
+	Until     time.Time
+	After     *pagination.Cursor
+	Limit     int
+}
+
+// ParseListing reads the filters, sort order and page of a chirp listing.
+// It returns a *ParamError for the first parameter that can't be used.
+func ParseListing(query url.Values) (Listing, error) {
+	var listing Listing
+
+	authorIDs, err := ParseAuthorIDs(query["author_id"])
+	if err != nil {
+		return Listing{}, err
+	}
+	listing.AuthorIDs = authorIDs
+
+	switch query.Get("sort") {
+	case "", "asc":
+	case "desc":
+		listing.Desc = true
+	default:
+		return Listing{}, &ParamError{"sort", "sort must be asc or desc"}
+	}
+
+	for _, bound := range []struct {
+		name string
+		dst  *time.Time
+	}{
+		{"since", &listing.Since},
+		{"until", &listing.Until},
+	} {
+		value := query.Get(bound.name)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return Listing{}, &ParamError{bound.name, bound.name + " must be an RFC 3339 timestamp such as 2025-01-31T12:00:00Z"}
+		}
+		*bound.dst = t
+	}
+	if !listing.Since.IsZero() && !listing.Until.IsZero() && !listing.Since.Before(listing.Until) {
+		return Listing{}, &ParamError{"until", "until must be after since"}
+	}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := pagination.DecodeCursor(cursor)
+		if err != nil {
+			return Listing{}, &ParamError{"cursor", "cursor must be a next_cursor returned by this endpoint"}
+		}
+		listing.After = &after
+	}
+
+	listing.Limit, err = pagination.ParseLimit(query.Get("limit"), DefaultLimit, MaxLimit)
+	if err != nil {
+		return Listing{}, &ParamError{"limit", err.Error()}
+	}
+
+	return listing, nil
+}
+
+// ParseAuthorIDs reads the author_id filter of chirp listings. Authors can
+// be given as repeated author_id parameters, as a comma separated list, or
+// both.
+func ParseAuthorIDs(values []string) ([]uuid.UUID, error) {
+	var result []uuid.UUID
+	for _, value := range values {
+		for _, authorID := range strings.Split(value, ",") {
+			authorID = strings.TrimSpace(authorID)
+			if authorID == "" {
+				continue
+			}
+			parsedAuthorID, err := uuid.Parse(authorID)
+			if err != nil {
+				return nil, &ParamError{"author_id", fmt.Sprintf("author_id %q is not a user id", authorID)}
+			}
+			result = append(result, parsedAuthorID)
+		}
+	}
+
+	if len(result) > MaxAuthors {
+		return nil, &ParamError{"author_id", fmt.Sprintf("at most %d authors can be given", MaxAuthors)}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,163 @@
+package chirpquery
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+func TestParseAuthorIDs(t *testing.T) {
+	a := uuid.MustParse("11111111-1111-1111-1111-111111111111")
+	b := uuid.MustParse("22222222-2222-2222-2222-222222222222")
+
+	manyIDs := make([]uuid.UUID, MaxAuthors+1)
+	many := make([]string, MaxAuthors+1)
+	for i := range many {
+		manyIDs[i] = uuid.New()
+		many[i] = manyIDs[i].String()
+	}
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    []uuid.UUID
+		wantErr bool
+	}{
+		{name: "none", values: nil, want: nil},
+		{name: "one", values: []string{a.String()}, want: []uuid.UUID{a}},
+		{name: "repeated", values: []string{a.String(), b.String()}, want: []uuid.UUID{a, b}},
+		{name: "comma separated", values: []string{a.String() + ", " + b.String()}, want: []uuid.UUID{a, b}},
+		{name: "empty entries", values: []string{",", a.String() + ",,"}, want: []uuid.UUID{a}},
+		{name: "not a uuid", values: []string{a.String() + ",nope"}, wantErr: true},
+		{name: "at the limit", values: many[:MaxAuthors], want: manyIDs[:MaxAuthors]},
+		{name: "too many", values: []string{strings.Join(many, ",")}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthorIDs(tt.values)
+			if tt.wantErr {
+				var paramErr *ParamError
+				if !errors.As(err, &paramErr) || paramErr.Param != "author_id" {
+					t.Fatalf("Expected an author_id ParamError, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseListing(t *testing.T) {
+	cursor := pagination.Cursor{
+		CreatedAt: time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC),
+		ID:        uuid.MustParse("11111111-1111-1111-1111-111111111111"),
+	}
+
+	tests := []struct {
+		name      string
+		query     string
+		check     func(t *testing.T, l Listing)
+		wantParam string
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			check: func(t *testing.T, l Listing) {
+				if l.Desc || !l.Since.IsZero() || !l.Until.IsZero() || l.After != nil || l.Limit != DefaultLimit {
+					t.Fatalf("Expected defaults, got %+v", l)
+				}
+			},
+		},
+		{
+			name:  "sort asc",
+			query: "sort=asc",
+			check: func(t *testing.T, l Listing) {
+				if l.Desc {
+					t.Fatal("Expected ascending order")
+				}
+			},
+		},
+		{
+			name:  "sort desc",
+			query: "sort=desc",
+			check: func(t *testing.T, l Listing) {
+				if !l.Desc {
+					t.Fatal("Expected descending order")
+				}
+			},
+		},
+		{name: "unknown sort", query: "sort=newest", wantParam: "sort"},
+		{
+			name:  "since and until",
+			query: "since=2025-01-01T00:00:00Z&until=2025-02-01T00:00:00%2B01:00",
+			check: func(t *testing.T, l Listing) {
+				if !l.Since.Equal(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)) {
+					t.Fatalf("Unexpected since %v", l.Since)
+				}
+				if !l.Until.Equal(time.Date(2025, 1, 31, 23, 0, 0, 0, time.UTC)) {
+					t.Fatalf("Unexpected until %v", l.Until)
+				}
+			},
+		},
+		{name: "since not a timestamp", query: "since=yesterday", wantParam: "since"},
+		{name: "until without zone", query: "until=2025-01-01T00:00:00", wantParam: "until"},
+		{name: "until equal to since", query: "since=2025-01-01T00:00:00Z&until=2025-01-01T00:00:00Z", wantParam: "until"},
+		{name: "until before since", query: "since=2025-02-01T00:00:00Z&until=2025-01-01T00:00:00Z", wantParam: "until"},
+		{
+			name:  "cursor",
+			query: "cursor=" + cursor.Encode(),
+			check: func(t *testing.T, l Listing) {
+				if l.After == nil || !l.After.CreatedAt.Equal(cursor.CreatedAt) || l.After.ID != cursor.ID {
+					t.Fatalf("Expected cursor %+v, got %+v", cursor, l.After)
+				}
+			},
+		},
+		{name: "invalid cursor", query: "cursor=nope", wantParam: "cursor"},
+		{
+			name:  "limit",
+			query: "limit=5",
+			check: func(t *testing.T, l Listing) {
+				if l.Limit != 5 {
+					t.Fatalf("Expected limit 5, got %d", l.Limit)
+				}
+			},
+		},
+		{name: "limit too large", query: fmt.Sprintf("limit=%d", MaxLimit+1), wantParam: "limit"},
+		{name: "invalid author", query: "author_id=nope", wantParam: "author_id"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatalf("Bad test query %q: %v", tt.query, err)
+			}
+
+			listing, err := ParseListing(query)
+			if tt.wantParam != "" {
+				var paramErr *ParamError
+				if !errors.As(err, &paramErr) || paramErr.Param != tt.wantParam {
+					t.Fatalf("Expected a %s ParamError, got %v", tt.wantParam, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			tt.check(t, listing)
+		})
+	}
+}
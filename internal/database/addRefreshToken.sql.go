@@ -14,16 +14,22 @@ import (
 
 const addRefreshToken = `-- name: AddRefreshToken :exec
 INSERT INTO refresh_tokens (
-  token, created_at, updated_at, user_id, experies_at  
-) VALUES ( $1, $2, $3, $4, $5 )
+  token, created_at, updated_at, user_id, experies_at, family_id,
+  session_started_at, last_used_at, user_agent, ip_address
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10 )
 `
 
 type AddRefreshTokenParams struct {
-	Token      string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	UserID     uuid.UUID
-	ExperiesAt time.Time
+	Token            string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	ExperiesAt       time.Time
+	FamilyID         uuid.UUID
+	SessionStartedAt time.Time
+	LastUsedAt       time.Time
+	UserAgent        string
+	IpAddress        string
 }
 
 func (q *Queries) AddRefreshToken(ctx context.Context, arg AddRefreshTokenParams) error {
@@ -33,6 +39,11 @@ func (q *Queries) AddRefreshToken(ctx context.Context, arg AddRefreshTokenParams
 		arg.UpdatedAt,
 		arg.UserID,
 		arg.ExperiesAt,
+		arg.FamilyID,
+		arg.SessionStartedAt,
+		arg.LastUsedAt,
+		arg.UserAgent,
+		arg.IpAddress,
 	)
 	return err
 }
@@ -14,9 +14,9 @@ import (
 
 const createChirp = `-- name: CreateChirp :one
 INSERT INTO chirps (
-  id, created_at, updated_at, body, user_id
-) VALUES ( $1, $2, $3, $4, $5 )
-returning id, created_at, updated_at, body, user_id
+  id, created_at, updated_at, body, user_id, status, in_reply_to
+) VALUES ( $1, $2, $3, $4, $5, $6, $7 )
+returning id, created_at, updated_at, body, user_id, status, in_reply_to, reply_count, deleted_at, search_vector
 `
 
 type CreateChirpParams struct {
@@ -24,7 +24,9 @@ type CreateChirpParams struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Body      string
-	UserID    uuid.UUID
+	UserID    uuid.NullUUID
+	Status    string
+	InReplyTo uuid.NullUUID
 }
 
 func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp, error) {
@@ -34,6 +36,8 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		arg.UpdatedAt,
 		arg.Body,
 		arg.UserID,
+		arg.Status,
+		arg.InReplyTo,
 	)
 	var i Chirp
 	err := row.Scan(
@@ -42,6 +46,11 @@ func (q *Queries) CreateChirp(ctx context.Context, arg CreateChirpParams) (Chirp
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.Status,
+		&i.InReplyTo,
+		&i.ReplyCount,
+		&i.DeletedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
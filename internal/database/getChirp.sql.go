@@ -12,7 +12,7 @@ import (
 )
 
 const getChirp = `-- name: GetChirp :one
-select id, created_at, updated_at, body, user_id from chirps
+select id, created_at, updated_at, body, user_id, status, in_reply_to, reply_count, deleted_at, search_vector from chirps
 where id = $1
 `
 
@@ -25,6 +25,11 @@ func (q *Queries) GetChirp(ctx context.Context, id uuid.UUID) (Chirp, error) {
 		&i.UpdatedAt,
 		&i.Body,
 		&i.UserID,
+		&i.Status,
+		&i.InReplyTo,
+		&i.ReplyCount,
+		&i.DeletedAt,
+		&i.SearchVector,
 	)
 	return i, err
 }
@@ -7,18 +7,42 @@ package database
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const getChirps = `-- name: GetChirps :many
-SELECT id, created_at, updated_at, body, user_id FROM chirps 
-WHERE ($1::uuid IS NULL OR user_id = $1)
-ORDER BY created_at ASC
+select id, created_at, updated_at, body, user_id, status, in_reply_to, reply_count, deleted_at, search_vector from chirps
+where status = 'published' and deleted_at is null
+  and ($1::uuid[] is null or user_id = any($1::uuid[]))
+  and ($2::timestamp is null or created_at >= $2)
+  and ($3::timestamp is null or created_at < $3)
+  and ($4::timestamp is null
+    or (created_at, id) > ($4, $5::uuid))
+order by created_at asc, id asc
+limit $6
 `
 
-func (q *Queries) GetChirps(ctx context.Context, dollar_1 uuid.UUID) ([]Chirp, error) {
-	rows, err := q.db.QueryContext(ctx, getChirps, dollar_1)
+type GetChirpsParams struct {
+	AuthorIds      []uuid.UUID
+	Since          sql.NullTime
+	Until          sql.NullTime
+	AfterCreatedAt sql.NullTime
+	AfterID        uuid.NullUUID
+	RowLimit       int32
+}
+
+func (q *Queries) GetChirps(ctx context.Context, arg GetChirpsParams) ([]Chirp, error) {
+	rows, err := q.db.QueryContext(ctx, getChirps,
+		pq.Array(arg.AuthorIds),
+		arg.Since,
+		arg.Until,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.RowLimit,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -32,6 +56,11 @@ func (q *Queries) GetChirps(ctx context.Context, dollar_1 uuid.UUID) ([]Chirp, e
 			&i.UpdatedAt,
 			&i.Body,
 			&i.UserID,
+			&i.Status,
+			&i.InReplyTo,
+			&i.ReplyCount,
+			&i.DeletedAt,
+			&i.SearchVector,
 		); err != nil {
 			return nil, err
 		}
@@ -10,7 +10,7 @@ import (
 )
 
 const getRefreshToken = `-- name: GetRefreshToken :one
-select token, created_at, updated_at, user_id, experies_at, revoked_at from refresh_tokens
+select token, created_at, updated_at, user_id, experies_at, revoked_at, family_id, session_started_at, last_used_at, user_agent, ip_address from refresh_tokens
 where token = $1
 `
 
@@ -24,6 +24,11 @@ func (q *Queries) GetRefreshToken(ctx context.Context, token string) (RefreshTok
 		&i.UserID,
 		&i.ExperiesAt,
 		&i.RevokedAt,
+		&i.FamilyID,
+		&i.SessionStartedAt,
+		&i.LastUsedAt,
+		&i.UserAgent,
+		&i.IpAddress,
 	)
 	return i, err
 }
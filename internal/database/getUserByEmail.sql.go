@@ -10,7 +10,7 @@ import (
 )
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-select id, created_at, updated_at, email, hashed_password, is_chirpy_red from users 
+select id, created_at, updated_at, email, hashed_password, is_chirpy_red, email_verified_at, banned_at, deletion_scheduled_at from users 
 where email = $1
 `
 
@@ -24,6 +24,9 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.Email,
 		&i.HashedPassword,
 		&i.IsChirpyRed,
+		&i.EmailVerifiedAt,
+		&i.BannedAt,
+		&i.DeletionScheduledAt,
 	)
 	return i, err
 }
@@ -11,28 +11,159 @@ import (
 	"github.com/google/uuid"
 )
 
+type AccessTokenCutoff struct {
+	UserID        uuid.UUID
+	RevokedBefore time.Time
+}
+
+type AccessTokenRevocation struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	ExpiresAt time.Time
+}
+
+type ApiKey struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Name       string
+	KeyHash    string
+	Prefix     string
+	Scopes     []string
+	CreatedAt  time.Time
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	RevokedAt  sql.NullTime
+}
+
 type Chirp struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Body         string
+	UserID       uuid.NullUUID
+	Status       string
+	InReplyTo    uuid.NullUUID
+	ReplyCount   int32
+	DeletedAt    sql.NullTime
+	SearchVector interface{}
+}
+
+type ChirpRevision struct {
+	ID         uuid.UUID
+	ChirpID    uuid.UUID
+	Body       string
+	CreatedAt  time.Time
+	ReplacedAt time.Time
+}
+
+type DataExport struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Status      string
+	Archive     []byte
+	CreatedAt   time.Time
+	CompletedAt sql.NullTime
+	ExpiresAt   time.Time
+}
+
+type LoginAttempt struct {
 	ID        uuid.UUID
+	Email     string
+	IpAddress string
+	UserAgent string
+	Succeeded bool
+	CreatedAt time.Time
+}
+
+type ModerationWord struct {
+	Word      string
+	Action    string
 	CreatedAt time.Time
 	UpdatedAt time.Time
-	Body      string
+}
+
+type OidcState struct {
+	State        string
+	Nonce        string
+	CodeVerifier string
+	CreatedAt    time.Time
+	ExpiresAt    time.Time
+}
+
+type OneTimeToken struct {
+	TokenHash string
+	Purpose   string
 	UserID    uuid.UUID
+	Payload   string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+	UsedAt    sql.NullTime
+	Attempts  int32
 }
 
 type RefreshToken struct {
-	Token      string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	UserID     uuid.UUID
-	ExperiesAt time.Time
-	RevokedAt  sql.NullTime
+	Token            string
+	CreatedAt        time.Time
+	UpdatedAt        time.Time
+	UserID           uuid.UUID
+	ExperiesAt       time.Time
+	RevokedAt        sql.NullTime
+	FamilyID         uuid.UUID
+	SessionStartedAt time.Time
+	LastUsedAt       time.Time
+	UserAgent        string
+	IpAddress        string
+}
+
+type UserIdentity struct {
+	Issuer    string
+	Subject   string
+	UserID    uuid.UUID
+	Email     string
+	CreatedAt time.Time
+}
+
+type UserRole struct {
+	UserID    uuid.UUID
+	Role      string
+	CreatedAt time.Time
+}
+
+type SubscriptionEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Event     string
+	CreatedAt time.Time
+}
+
+type ThrottleEvent struct {
+	Key       string
+	CreatedAt time.Time
+}
+
+type TotpRecoveryCode struct {
+	CodeHash  string
+	UserID    uuid.UUID
+	CreatedAt time.Time
+	UsedAt    sql.NullTime
 }
 
 type User struct {
-	ID             uuid.UUID
-	CreatedAt      time.Time
-	UpdatedAt      time.Time
-	Email          string
-	HashedPassword string
-	IsChirpyRed    sql.NullBool
+	ID                  uuid.UUID
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+	Email               string
+	HashedPassword      string
+	IsChirpyRed         sql.NullBool
+	EmailVerifiedAt     sql.NullTime
+	BannedAt            sql.NullTime
+	DeletionScheduledAt sql.NullTime
+}
+
+type UserTotp struct {
+	UserID       uuid.UUID
+	Secret       string
+	CreatedAt    time.Time
+	ConfirmedAt  sql.NullTime
+	LastUsedStep int64
 }
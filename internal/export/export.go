@@ -0,0 +1,77 @@
+package export
+
+import (
+	"archive/zip"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"time"
+)
+
+// Archive writes a personal data export: a zip of JSON files, with a CSV
+// copy of every table so the data opens in a spreadsheet as well.
+type Archive struct {
+	zw      *zip.Writer
+	created time.Time
+}
+
+func NewArchive(w io.Writer, created time.Time) *Archive {
+	return &Archive{zw: zip.NewWriter(w), created: created}
+}
+
+func (a *Archive) create(name string) (io.Writer, error) {
+	return a.zw.CreateHeader(&zip.FileHeader{
+		Name:     name,
+		Method:   zip.Deflate,
+		Modified: a.created,
+	})
+}
+
+// AddJSON adds name to the archive holding v as indented JSON.
+func (a *Archive) AddJSON(name string, v any) error {
+	f, err := a.create(name)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(f)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// AddCSV adds name to the archive with a header line followed by rows.
+func (a *Archive) AddCSV(name string, header []string, rows [][]string) error {
+	f, err := a.create(name)
+	if err != nil {
+		return err
+	}
+
+	writer := csv.NewWriter(f)
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// AddTable adds a table as both name.json and name.csv.
+func (a *Archive) AddTable(name string, v any, header []string, rows [][]string) error {
+	if err := a.AddJSON(name+".json", v); err != nil {
+		return err
+	}
+	return a.AddCSV(name+".csv", header, rows)
+}
+
+func (a *Archive) Close() error {
+	return a.zw.Close()
+}
+
+// FormatTime formats t for CSV files; the zero time is left empty.
+func FormatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
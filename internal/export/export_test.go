@@ -0,0 +1,64 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestArchive(t *testing.T) {
+	var buf bytes.Buffer
+	a := NewArchive(&buf, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC))
+
+	if err := a.AddJSON("profile.json", map[string]string{"email": "jane@example.com"}); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	rows := [][]string{{"1", "hello, world"}, {"2", `say "hi"`}}
+	if err := a.AddTable("chirps", rows, []string{"id", "body"}, rows); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if err := a.Close(); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to read archive: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", f.Name, err)
+		}
+		data, _ := io.ReadAll(rc)
+		rc.Close()
+		files[f.Name] = string(data)
+	}
+
+	var profile map[string]string
+	if err := json.Unmarshal([]byte(files["profile.json"]), &profile); err != nil || profile["email"] != "jane@example.com" {
+		t.Fatalf("Unexpected profile.json: %q", files["profile.json"])
+	}
+
+	wantCSV := "id,body\n1,\"hello, world\"\n2,\"say \"\"hi\"\"\"\n"
+	if files["chirps.csv"] != wantCSV {
+		t.Fatalf("Expected chirps.csv %q, got %q", wantCSV, files["chirps.csv"])
+	}
+	if _, ok := files["chirps.json"]; !ok {
+		t.Fatal("Expected chirps.json in the archive")
+	}
+}
+
+func TestFormatTime(t *testing.T) {
+	if FormatTime(time.Time{}) != "" {
+		t.Fatal("Expected zero time to format as empty")
+	}
+	if got := FormatTime(time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)); got != "2025-01-01T12:00:00Z" {
+		t.Fatalf("Unexpected formatted time %q", got)
+	}
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefixLength is how much of a key we keep in the clear so users can
+// tell their keys apart.
+const apiKeyPrefixLength = len(auth.APIKeyPrefix) + 8
+
+// HandleCreateAPIKey issues a personal API key. The key is only ever shown in
+// this response. Keys can't be used to create more keys.
+func (cfg *ApiConfig) HandleCreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Name          string   `json:"name"`
+		Scopes        []string `json:"scopes"`
+		ExpiresInDays int      `json:"expires_in_days"`
+	}
+
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if params.Name == "" || len(params.Scopes) == 0 || params.ExpiresInDays < 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("A name and at least one scope are required"))
+		return
+	}
+
+	scopes := []string{}
+	for _, s := range params.Scopes {
+		scope, err := auth.ParseScope(s)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(err.Error()))
+			return
+		}
+		scopes = append(scopes, string(scope))
+	}
+
+	key, err := auth.MakeAPIKey()
+	if err != nil {
+		log.Printf("failed to create api key: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	now := time.Now()
+	expiresAt := sql.NullTime{}
+	if params.ExpiresInDays > 0 {
+		expiresAt = sql.NullTime{Valid: true, Time: now.AddDate(0, 0, params.ExpiresInDays)}
+	}
+
+	row, err := cfg.DB.CreateAPIKey(r.Context(), database.CreateAPIKeyParams{
+		ID:        uuid.New(),
+		UserID:    userID,
+		Name:      params.Name,
+		KeyHash:   auth.HashToken(key),
+		Prefix:    key[:apiKeyPrefixLength],
+		Scopes:    scopes,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		log.Printf("failed to store api key: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	responseBody := newAPIKey(row)
+	responseBody.Key = key
+
+	data, err := json.Marshal(responseBody)
+	if err != nil {
+		log.Printf("failed to marshal api key: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write(data)
+}
+
+func (cfg *ApiConfig) HandleListAPIKeys(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	rows, err := cfg.DB.ListAPIKeys(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to list api keys: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := []APIKey{}
+	for _, row := range rows {
+		result = append(result, newAPIKey(row))
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal api keys: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (cfg *ApiConfig) HandleRevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	keyID, err := uuid.Parse(r.PathValue("keyId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeAPIKey(r.Context(), database.RevokeAPIKeyParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		ID:        keyID,
+		UserID:    userID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke api key: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if revoked == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func newAPIKey(row database.ApiKey) APIKey {
+	key := APIKey{
+		ID:        row.ID,
+		Name:      row.Name,
+		Prefix:    row.Prefix,
+		Scopes:    row.Scopes,
+		CreatedAt: row.CreatedAt,
+	}
+	if row.ExpiresAt.Valid {
+		key.ExpiresAt = &row.ExpiresAt.Time
+	}
+	if row.LastUsedAt.Valid {
+		key.LastUsedAt = &row.LastUsedAt.Time
+	}
+	return key
+}
@@ -3,15 +3,24 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"log"
 	"net/http"
 	"time"
 
+	"github.com/HellYeahOmg/Chirpy/internal/account"
 	"github.com/HellYeahOmg/Chirpy/internal/auth"
 	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/session"
 	"github.com/google/uuid"
 )
 
+// AccessTokenTTL is exported so revocations can be kept exactly as long as
+// the tokens they reject.
+const AccessTokenTTL = time.Hour
+
+const refreshTokenTTL = 60 * 24 * time.Hour
+
 func (cfg *ApiConfig) HandleLogin(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
 		Email    string `json:"email"`
@@ -27,66 +36,120 @@ func (cfg *ApiConfig) HandleLogin(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if !cfg.checkLoginThrottle(w, r, params.Email) {
+		return
+	}
+
 	row, err := cfg.DB.GetUserByEmail(r.Context(), params.Email)
 	if err != nil {
+		cfg.recordLoginAttempt(r, params.Email, false)
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte("Incorrect email or password"))
 		return
 	}
 
-	if auth.CheckPasswordHash(params.Password, row.HashedPassword) != nil {
+	if cfg.Passwords.Check(params.Password, row.HashedPassword) != nil {
+		cfg.recordLoginAttempt(r, params.Email, false)
 		w.WriteHeader(http.StatusUnauthorized)
 		w.Write([]byte("Incorrect email or password"))
 		return
 	}
 
-	type response struct {
-		ID           uuid.UUID `json:"id"`
-		CreatedAt    time.Time `json:"created_at"`
-		UpdatedAt    time.Time `json:"updated_at"`
-		Email        string    `json:"email"`
-		IsChirpyRed  bool      `json:"is_chirpy_red"`
-		Token        string    `json:"token"`
-		RefreshToken string    `json:"refresh_token"`
+	if cfg.Passwords.NeedsRehash(row.HashedPassword) {
+		cfg.rehashPassword(r, row.ID, params.Password)
 	}
 
-	accessToken, err := auth.MakeJWT(row.ID, cfg.JwtSecret)
-	if err != nil {
-		log.Printf("failed to create jwt token: %s", err)
-		w.WriteHeader(500)
+	cfg.finishLogin(w, r, row)
+}
+
+// finishLogin takes over once the user has proven their first factor,
+// whether a password or an external identity provider. It applies the
+// checks every login goes through before a session is issued.
+func (cfg *ApiConfig) finishLogin(w http.ResponseWriter, r *http.Request, row database.User) {
+	if !cfg.checkCanLogIn(w, row) {
 		return
 	}
 
-	refreshToken, err := auth.MakeRefreshToken()
-	if err != nil {
-		log.Printf("failed to create refresh token: %s", err)
+	totp, err := cfg.DB.GetUserTOTP(r.Context(), row.ID)
+	if err == nil && totp.ConfirmedAt.Valid {
+		cfg.startLoginChallenge(w, r, row)
+		return
+	}
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("failed to look up 2fa settings: %s", err)
 		w.WriteHeader(500)
 		return
 	}
 
-	rtRow := database.AddRefreshTokenParams{
-		Token:      refreshToken,
-		CreatedAt:  time.Now(),
-		UpdatedAt:  time.Now(),
-		UserID:     row.ID,
-		ExperiesAt: time.Now().AddDate(0, 0, 60),
+	cfg.recordLoginAttempt(r, row.Email, true)
+	cfg.completeLogin(w, r, row)
+}
+
+// checkCanLogIn answers 403 and returns false when the account may not get a
+// session at all. It runs again at the 2FA step, since the account may have
+// been banned in between.
+func (cfg *ApiConfig) checkCanLogIn(w http.ResponseWriter, row database.User) bool {
+	state := account.LoginState{Banned: row.BannedAt.Valid, EmailVerified: row.EmailVerifiedAt.Valid}
+	if err := account.CheckLogin(state, cfg.RequireVerifiedEmailToLogin); err != nil {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(err.Error()))
+		return false
 	}
+	return true
+}
 
-	err = cfg.DB.AddRefreshToken(r.Context(), rtRow)
+// completeLogin issues a new session for a user who has proven who they are
+// and answers with the user and both tokens.
+func (cfg *ApiConfig) completeLogin(w http.ResponseWriter, r *http.Request, row database.User) {
+	type response struct {
+		ID            uuid.UUID `json:"id"`
+		CreatedAt     time.Time `json:"created_at"`
+		UpdatedAt     time.Time `json:"updated_at"`
+		Email         string    `json:"email"`
+		EmailVerified bool      `json:"email_verified"`
+		IsChirpyRed   bool      `json:"is_chirpy_red"`
+		Token         string    `json:"token"`
+		RefreshToken  string    `json:"refresh_token,omitempty"`
+		CSRFToken     string    `json:"csrf_token,omitempty"`
+	}
+
+	cfg.cancelAccountDeletion(r, row)
+
+	familyID := uuid.New()
+	accessToken, err := cfg.makeAccessToken(r, row.ID, familyID)
 	if err != nil {
-		log.Printf("failed to save refresh token to db: %s", err)
+		log.Printf("failed to create jwt token: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	refreshToken, err := cfg.issueRefreshToken(r, row.ID, familyID, time.Now())
+	if err != nil {
+		log.Printf("failed to issue refresh token: %s", err)
 		w.WriteHeader(500)
 		return
 	}
 
 	responseBody := response{
-		ID:           row.ID,
-		CreatedAt:    row.CreatedAt,
-		UpdatedAt:    row.UpdatedAt,
-		Email:        row.Email,
-		IsChirpyRed:  row.IsChirpyRed.Bool,
-		Token:        accessToken,
-		RefreshToken: refreshToken,
+		ID:            row.ID,
+		CreatedAt:     row.CreatedAt,
+		UpdatedAt:     row.UpdatedAt,
+		Email:         row.Email,
+		EmailVerified: row.EmailVerifiedAt.Valid,
+		IsChirpyRed:   row.IsChirpyRed.Bool,
+		Token:         accessToken,
+		RefreshToken:  refreshToken,
+	}
+
+	// In cookie mode the refresh token never reaches the client's scripts.
+	if wantsCookieSession(r) {
+		responseBody.CSRFToken, err = setSessionCookies(w, refreshToken)
+		if err != nil {
+			log.Printf("failed to create csrf token: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+		responseBody.RefreshToken = ""
 	}
 
 	data, err := json.Marshal(responseBody)
@@ -99,32 +162,76 @@ func (cfg *ApiConfig) HandleLogin(w http.ResponseWriter, r *http.Request) {
 }
 
 func (cfg *ApiConfig) HandleRefresh(w http.ResponseWriter, r *http.Request) {
-	token, err := auth.GetBearerToken(r.Header)
+	token, fromCookie, err := getRefreshToken(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeRefreshTokenError(w, err)
 		return
 	}
 
 	row, err := cfg.DB.GetRefreshToken(r.Context(), token)
-	if err != nil || row.RevokedAt.Valid {
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	switch session.CheckRefresh(row.RevokedAt.Valid, row.ExperiesAt, time.Now()) {
+	case session.RefreshReused:
+		// Either the client or an attacker is replaying an already rotated
+		// token. We can't tell which, so the whole family is revoked and
+		// the user has to log in.
+		log.Printf("refresh token reuse detected for user %s", row.UserID)
+		cfg.revokeRefreshTokenFamily(r, row.FamilyID)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	case session.RefreshExpired:
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	claimed, err := cfg.DB.ClaimRefreshToken(r.Context(), database.ClaimRefreshTokenParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		Token:     token,
+	})
+	if err != nil {
+		log.Printf("failed to rotate refresh token: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	// Someone else rotated this token between our read and the claim.
+	if claimed == 0 {
+		log.Printf("refresh token reuse detected for user %s", row.UserID)
+		cfg.revokeRefreshTokenFamily(r, row.FamilyID)
 		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
 	type response struct {
-		Token string `json:"token"`
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token,omitempty"`
 	}
 
-	accessToken, err := auth.MakeJWT(row.UserID, cfg.JwtSecret)
+	accessToken, err := cfg.makeAccessToken(r, row.UserID, row.FamilyID)
 	if err != nil {
 		log.Printf("failed to create jwt token: %s", err)
 		w.WriteHeader(500)
 		return
+	}
 
+	refreshToken, err := cfg.issueRefreshToken(r, row.UserID, row.FamilyID, row.SessionStartedAt)
+	if err != nil {
+		log.Printf("failed to issue refresh token: %s", err)
+		w.WriteHeader(500)
+		return
 	}
 
 	responseBody := response{
-		Token: accessToken,
+		Token:        accessToken,
+		RefreshToken: refreshToken,
+	}
+	if fromCookie {
+		setRefreshTokenCookie(w, refreshToken)
+		responseBody.RefreshToken = ""
 	}
 
 	data, err := json.Marshal(responseBody)
@@ -139,12 +246,18 @@ func (cfg *ApiConfig) HandleRefresh(w http.ResponseWriter, r *http.Request) {
 }
 
 func (cfg *ApiConfig) HandleRevoke(w http.ResponseWriter, r *http.Request) {
-	refreshToken, err := auth.GetBearerToken(r.Header)
+	refreshToken, fromCookie, err := getRefreshToken(r)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		writeRefreshTokenError(w, err)
 		return
 	}
 
+	// Logging out ends the access tokens of the session as well.
+	row, err := cfg.DB.GetRefreshToken(r.Context(), refreshToken)
+	if err == nil {
+		cfg.revokeSessionAccessTokens(r, row.FamilyID)
+	}
+
 	input := database.UpdateRefreshTokenParams{
 		RevokedAt: sql.NullTime{
 			Valid: true,
@@ -159,5 +272,114 @@ func (cfg *ApiConfig) HandleRevoke(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if fromCookie {
+		clearSessionCookies(w)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// rehashPassword upgrades a stored hash to the current scheme and parameters
+// while the plain password is at hand. Failing to do so doesn't fail the
+// login; we'll try again next time.
+func (cfg *ApiConfig) rehashPassword(r *http.Request, userID uuid.UUID, password string) {
+	hash, err := cfg.Passwords.Hash(password)
+	if err != nil {
+		log.Printf("failed to rehash password: %s", err)
+		return
+	}
+
+	err = cfg.DB.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+		HashedPassword: hash,
+		UpdatedAt:      time.Now(),
+		ID:             userID,
+	})
+	if err != nil {
+		log.Printf("failed to store rehashed password: %s", err)
+	}
+}
+
+// makeAccessToken signs an access token carrying the user's current roles.
+// The session is the refresh token family, so logging a session out can
+// revoke its access tokens too.
+func (cfg *ApiConfig) makeAccessToken(r *http.Request, userID, sessionID uuid.UUID) (string, error) {
+	roles, err := cfg.DB.GetUserRoles(r.Context(), userID)
+	if err != nil {
+		return "", err
+	}
+
+	return cfg.Keys.MakeJWT(auth.TokenParams{
+		UserID:    userID,
+		SessionID: sessionID,
+		Roles:     roles,
+		ExpiresIn: AccessTokenTTL,
+	})
+}
+
+// issueRefreshToken stores a new refresh token for the user. Tokens created
+// by rotating an existing one share its family so that reuse of any of them
+// can take the whole chain down. A family is what users see as a session, so
+// the device metadata of the request is recorded with every token.
+func (cfg *ApiConfig) issueRefreshToken(r *http.Request, userID, familyID uuid.UUID, sessionStartedAt time.Time) (string, error) {
+	refreshToken, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = cfg.DB.AddRefreshToken(r.Context(), database.AddRefreshTokenParams{
+		Token:            refreshToken,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		UserID:           userID,
+		ExperiesAt:       now.Add(refreshTokenTTL),
+		FamilyID:         familyID,
+		SessionStartedAt: sessionStartedAt,
+		LastUsedAt:       now,
+		UserAgent:        session.UserAgent(r.UserAgent()),
+		IpAddress:        cfg.clientIP(r),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return refreshToken, nil
+}
+
+func (cfg *ApiConfig) revokeRefreshTokenFamily(r *http.Request, familyID uuid.UUID) {
+	err := cfg.DB.RevokeRefreshTokenFamily(r.Context(), database.RevokeRefreshTokenFamilyParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		FamilyID:  familyID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke refresh token family %s: %s", familyID, err)
+	}
+
+	cfg.revokeSessionAccessTokens(r, familyID)
+}
+
+// revokeSessionAccessTokens rejects the access tokens issued for a session
+// before they expire.
+func (cfg *ApiConfig) revokeSessionAccessTokens(r *http.Request, sessionID uuid.UUID) {
+	if cfg.Revocations == nil {
+		return
+	}
+
+	err := cfg.Revocations.Revoke(r.Context(), sessionID)
+	if err != nil {
+		log.Printf("failed to revoke access tokens of session %s: %s", sessionID, err)
+	}
+}
+
+// revokeUserAccessTokens rejects every access token the user holds, e.g.
+// after a password change.
+func (cfg *ApiConfig) revokeUserAccessTokens(r *http.Request, userID uuid.UUID) {
+	if cfg.Revocations == nil {
+		return
+	}
+
+	err := cfg.Revocations.RevokeUser(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to revoke access tokens of user %s: %s", userID, err)
+	}
+}
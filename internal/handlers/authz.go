@@ -0,0 +1,159 @@
+package handlers
+
+import (
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+)
+
+var errUnauthenticated = errors.New("missing or invalid credentials")
+
+// MiddlewareAuthenticate only lets requests through that carry a valid
+// access token, and makes their principal available through
+// auth.MustPrincipal. API keys are refused: they only work on routes that
+// name the scope they need.
+func (cfg *ApiConfig) MiddlewareAuthenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := cfg.requirePrincipal(w, r)
+		if !ok {
+			return
+		}
+
+		if principal.Method != auth.MethodAccessToken {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("API keys can't be used here"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithPrincipal(r.Context(), principal)))
+	})
+}
+
+// MiddlewareRequireScope lets requests through that carry an access token or
+// an API key holding scope.
+func (cfg *ApiConfig) MiddlewareRequireScope(scope auth.Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal, ok := cfg.requirePrincipal(w, r)
+		if !ok {
+			return
+		}
+
+		if !principal.HasScope(scope) {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("API key lacks the " + string(scope) + " scope"))
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(auth.ContextWithPrincipal(r.Context(), principal)))
+	})
+}
+
+// MiddlewareOptionalAuth is for public routes that may answer differently
+// when the caller is known. Anonymous requests go through without a
+// principal; credentials that are sent still have to be valid and hold
+// scope, so a typo in a key doesn't silently turn into anonymous access.
+func (cfg *ApiConfig) MiddlewareOptionalAuth(scope auth.Scope, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cfg.MiddlewareRequireScope(scope, next).ServeHTTP(w, r)
+	})
+}
+
+// MiddlewareRequireRole rejects requests whose access token doesn't carry
+// the required role, or a more privileged one. Roles are read from the token
+// claims, so a grant or revoke takes effect once the user refreshes.
+func (cfg *ApiConfig) MiddlewareRequireRole(role auth.Role, next http.Handler) http.Handler {
+	return cfg.MiddlewareAuthenticate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !auth.MustPrincipal(r.Context()).HasRole(role) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	}))
+}
+
+// requirePrincipal authenticates the request and writes the error response
+// itself when that fails.
+func (cfg *ApiConfig) requirePrincipal(w http.ResponseWriter, r *http.Request) (*auth.Principal, bool) {
+	principal, err := cfg.authenticateRequest(r)
+	if errors.Is(err, errUnauthenticated) {
+		w.WriteHeader(http.StatusUnauthorized)
+		return nil, false
+	}
+	if err != nil {
+		log.Printf("failed to authenticate request: %s", err)
+		w.WriteHeader(500)
+		return nil, false
+	}
+	return principal, true
+}
+
+// authenticateRequest returns the caller behind either a Bearer access token
+// or an ApiKey personal key.
+func (cfg *ApiConfig) authenticateRequest(r *http.Request) (*auth.Principal, error) {
+	if !strings.HasPrefix(r.Header.Get("Authorization"), "ApiKey ") {
+		accessToken, err := auth.GetBearerToken(r.Header)
+		if err != nil {
+			return nil, errUnauthenticated
+		}
+
+		claims, err := cfg.Keys.ParseJWT(accessToken)
+		if err != nil {
+			return nil, errUnauthenticated
+		}
+
+		userID, err := claims.UserID()
+		if err != nil {
+			return nil, errUnauthenticated
+		}
+
+		return &auth.Principal{
+			UserID:    userID,
+			Roles:     claims.Roles,
+			Method:    auth.MethodAccessToken,
+			SessionID: claims.Session(),
+		}, nil
+	}
+
+	key, err := auth.GetAPIKey(r.Header)
+	if err != nil {
+		return nil, errUnauthenticated
+	}
+
+	now := time.Now()
+	row, err := cfg.DB.GetAPIKeyByHash(r.Context(), database.GetAPIKeyByHashParams{
+		KeyHash:   auth.HashToken(key),
+		ExpiresAt: sql.NullTime{Valid: true, Time: now},
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, errUnauthenticated
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.DB.TouchAPIKey(r.Context(), database.TouchAPIKeyParams{
+		LastUsedAt: sql.NullTime{Valid: true, Time: now},
+		ID:         row.ID,
+	})
+	if err != nil {
+		log.Printf("failed to record api key use: %s", err)
+	}
+
+	return &auth.Principal{
+		UserID: row.UserID,
+		Method: auth.MethodAPIKey,
+		Scopes: row.Scopes,
+	}, nil
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"database/sql"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// HandleBanUser locks a user out: they can't log in anymore, and every
+// refresh token, access token and API key they hold stops working at once.
+func (cfg *ApiConfig) HandleBanUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	updated, err := cfg.DB.SetUserBanned(r.Context(), database.SetUserBannedParams{
+		BannedAt:  sql.NullTime{Valid: true, Time: now},
+		UpdatedAt: now,
+		ID:        userID,
+	})
+	if err != nil {
+		log.Printf("failed to ban user: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if updated == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err = cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: now},
+		UserID:    userID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens of banned user: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.revokeUserAccessTokens(r, userID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleUnbanUser lets a banned user log in again. Their old sessions stay
+// revoked; API keys work again.
+func (cfg *ApiConfig) HandleUnbanUser(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	updated, err := cfg.DB.SetUserBanned(r.Context(), database.SetUserBannedParams{
+		BannedAt:  sql.NullTime{},
+		UpdatedAt: time.Now(),
+		ID:        userID,
+	})
+	if err != nil {
+		log.Printf("failed to unban user: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if updated == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
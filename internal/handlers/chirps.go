@@ -1,20 +1,28 @@
 package handlers
 
 import (
+	"context"
+	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"time"
 
 	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/chirpquery"
 	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/moderation"
+	"github.com/HellYeahOmg/Chirpy/internal/pagination"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func (cfg *ApiConfig) HandleCreateChirp(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		Body string `json:"body"`
+		Body      string  `json:"body"`
+		InReplyTo *string `json:"in_reply_to"`
 	}
 
 	type errorReturnValues struct {
@@ -42,32 +50,40 @@ func (cfg *ApiConfig) HandleCreateChirp(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	token, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
+	id := auth.MustPrincipal(r.Context()).UserID
 
-	id, err := auth.ValidateJWT(token, cfg.JwtSecret)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
+	if cfg.RequireVerifiedEmailToChirp {
+		user, err := cfg.DB.GetUser(r.Context(), id)
+		if err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
 
-	if len(params.Body) > 140 {
-		w.WriteHeader(400)
-		responseBody := errorReturnValues{
-			Error: "Chirp is too long",
+		if !user.EmailVerifiedAt.Valid {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Email address is not verified"))
+			return
 		}
+	}
 
-		dat, err := json.Marshal(responseBody)
+	inReplyTo := uuid.NullUUID{}
+	if params.InReplyTo != nil {
+		parentID, err := uuid.Parse(*params.InReplyTo)
 		if err != nil {
-			w.WriteHeader(500)
-			log.Printf("Error marshalling JSON: %s", err)
+			writeChirpError(w, "in_reply_to must be a chirp id")
 			return
 		}
 
-		w.Write(dat)
+		parent, err := cfg.DB.GetChirp(r.Context(), parentID)
+		if err != nil || parent.Status != chirpStatusPublished || parent.DeletedAt.Valid {
+			writeChirpError(w, "Chirp to reply to was not found")
+			return
+		}
+		inReplyTo = uuid.NullUUID{Valid: true, UUID: parent.ID}
+	}
+
+	body, status, ok := cfg.checkChirpBody(w, params.Body)
+	if !ok {
 		return
 	}
 
@@ -75,24 +91,26 @@ func (cfg *ApiConfig) HandleCreateChirp(w http.ResponseWriter, r *http.Request)
 		ID:        uuid.New(),
 		CreatedAt: time.Now(),
 		UpdatedAt: time.Now(),
-		Body:      params.Body,
-		UserID:    id,
+		Body:      body,
+		UserID:    uuid.NullUUID{Valid: true, UUID: id},
+		Status:    status,
+		InReplyTo: inReplyTo,
 	}
 
 	result, err := cfg.DB.CreateChirp(r.Context(), newChirp)
+	// The parent was deleted since it was looked up.
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		writeChirpError(w, "Chirp to reply to was not found")
+		return
+	}
 	if err != nil {
 		log.Printf("failed to create a new chirp: %s", err)
 		w.WriteHeader(500)
 		return
 	}
 
-	responseBody := Chirp{
-		ID:        result.ID,
-		UpdatedAt: result.UpdatedAt,
-		CreatedAt: result.CreatedAt,
-		Body:      result.Body,
-		UserID:    result.UserID,
-	}
+	responseBody := chirpFromRow(result)
 
 	data, err := json.Marshal(responseBody)
 	if err != nil {
@@ -105,38 +123,105 @@ func (cfg *ApiConfig) HandleCreateChirp(w http.ResponseWriter, r *http.Request)
 	w.Write(data)
 }
 
-func (cfg *ApiConfig) HandleGetChirps(w http.ResponseWriter, r *http.Request) {
-	authorID := r.URL.Query().Get("author_id")
-	result := []Chirp{}
+// maxChirpLength is the longest chirp body in bytes.
+const maxChirpLength = 140
+
+// checkChirpBody applies the rules every chirp body has to pass, whether
+// new or edited. When the body is refused it answers the request and
+// returns false; otherwise it returns the body to store, with masked words
+// replaced, and the status the chirp gets.
+func (cfg *ApiConfig) checkChirpBody(w http.ResponseWriter, body string) (string, string, bool) {
+	if len(body) > maxChirpLength {
+		writeChirpError(w, "Chirp is too long")
+		return "", "", false
+	}
 
-	var parsedAuthorID uuid.UUID
-	var err error
+	moderated := cfg.Moderation.Check(body)
+	if moderated.Action == moderation.ActionReject {
+		writeChirpError(w, "Chirp contains a banned word")
+		return "", "", false
+	}
 
-	if authorID != "" {
-		parsedAuthorID, err = uuid.Parse(authorID)
-		if err != nil {
-			log.Printf("failed to parse author_id: %s", err)
-			w.WriteHeader(http.StatusBadRequest)
-			return
-		}
+	// A mask can be longer than the word it replaces, so the stored body
+	// has to fit as well.
+	if len(moderated.Text) > maxChirpLength {
+		writeChirpError(w, "Chirp is too long")
+		return "", "", false
+	}
+
+	status := chirpStatusPublished
+	if moderated.Action == moderation.ActionHold {
+		status = chirpStatusHeld
+	}
+
+	return moderated.Text, status, true
+}
+
+func writeChirpError(w http.ResponseWriter, message string) {
+	type errorReturnValues struct {
+		Error string `json:"valid"`
 	}
 
-	rows, err := cfg.DB.GetChirps(r.Context(), parsedAuthorID)
+	dat, err := json.Marshal(errorReturnValues{Error: message})
+	if err != nil {
+		log.Printf("Error marshalling JSON: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(400)
+	w.Write(dat)
+}
+
+// HandleGetChirps lists published chirps a page at a time. Pages are
+// addressed by the cursor of the last chirp of the previous page, so each
+// one is an index seek no matter how deep a client has scrolled. A cursor
+// is only meaningful with the same filters and sort order it came from.
+func (cfg *ApiConfig) HandleGetChirps(w http.ResponseWriter, r *http.Request) {
+	listing, err := chirpquery.ParseListing(r.URL.Query())
+	if err != nil {
+		writeParamError(w, err)
+		return
+	}
+
+	params := database.GetChirpsParams{AuthorIds: listing.AuthorIDs}
+	// created_at holds the server's local wall clock time.
+	if !listing.Since.IsZero() {
+		params.Since = sql.NullTime{Valid: true, Time: listing.Since.Local()}
+	}
+	if !listing.Until.IsZero() {
+		params.Until = sql.NullTime{Valid: true, Time: listing.Until.Local()}
+	}
+	if listing.After != nil {
+		params.AfterCreatedAt = sql.NullTime{Valid: true, Time: listing.After.CreatedAt}
+		params.AfterID = uuid.NullUUID{Valid: true, UUID: listing.After.ID}
+	}
+	// One extra row tells whether there is a next page.
+	limit := listing.Limit
+	params.RowLimit = int32(limit + 1)
+
+	var rows []database.Chirp
+	if listing.Desc {
+		rows, err = cfg.DB.GetChirpsDesc(r.Context(), database.GetChirpsDescParams(params))
+	} else {
+		rows, err = cfg.DB.GetChirps(r.Context(), params)
+	}
 	if err != nil {
 		log.Printf("failed to get chirps: %s", err)
 		w.WriteHeader(500)
 		return
 	}
 
+	result := ChirpPage{Chirps: []Chirp{}}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		next := pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID}.Encode()
+		result.NextCursor = &next
+	}
+
 	for _, item := range rows {
-		jsonItem := Chirp{
-			ID:        item.ID,
-			CreatedAt: item.CreatedAt,
-			UpdatedAt: item.UpdatedAt,
-			Body:      item.Body,
-			UserID:    item.UserID,
-		}
-		result = append(result, jsonItem)
+		result.Chirps = append(result.Chirps, chirpFromRow(item))
 	}
 
 	data, err := json.Marshal(result)
@@ -146,8 +231,38 @@ func (cfg *ApiConfig) HandleGetChirps(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	w.Write(data)
 	w.WriteHeader(200)
+	w.Write(data)
+}
+
+// writeParamError answers a request with a query parameter that
+// chirpquery refused.
+func writeParamError(w http.ResponseWriter, err error) {
+	var paramErr *chirpquery.ParamError
+	if errors.As(err, &paramErr) {
+		writeQueryParamError(w, paramErr.Param, paramErr.Message)
+		return
+	}
+	w.WriteHeader(http.StatusBadRequest)
+}
+
+// writeQueryParamError answers a request with an unusable query parameter.
+func writeQueryParamError(w http.ResponseWriter, param, message string) {
+	type response struct {
+		Error string `json:"error"`
+		Param string `json:"param"`
+	}
+
+	data, err := json.Marshal(response{Error: message, Param: param})
+	if err != nil {
+		log.Printf("failed to marshal query parameter error: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(data)
 }
 
 func (cfg *ApiConfig) HandleGetChirp(w http.ResponseWriter, r *http.Request) {
@@ -166,14 +281,13 @@ func (cfg *ApiConfig) HandleGetChirp(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	chirp := Chirp{
-		ID:        row.ID,
-		CreatedAt: row.CreatedAt,
-		UpdatedAt: row.UpdatedAt,
-		UserID:    row.UserID,
-		Body:      row.Body,
+	if row.Status == chirpStatusHeld && !canSeeHeldChirp(r, row) {
+		w.WriteHeader(404)
+		return
 	}
 
+	chirp := chirpFromRow(row)
+
 	data, err := json.Marshal(chirp)
 	if err != nil {
 		fmt.Printf("failed to marshal chirp: %s", err)
@@ -186,17 +300,7 @@ func (cfg *ApiConfig) HandleGetChirp(w http.ResponseWriter, r *http.Request) {
 }
 
 func (cfg *ApiConfig) HandleDeleteChirp(w http.ResponseWriter, r *http.Request) {
-	accessToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
-
-	userID, err := auth.ValidateJWT(accessToken, cfg.JwtSecret)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
-	}
+	userID := auth.MustPrincipal(r.Context()).UserID
 
 	chirpID := r.PathValue("chirpId")
 	parsedChirpID, err := uuid.Parse(chirpID)
@@ -205,21 +309,65 @@ func (cfg *ApiConfig) HandleDeleteChirp(w http.ResponseWriter, r *http.Request)
 	}
 
 	row, err := cfg.DB.GetChirp(r.Context(), parsedChirpID)
-	if err != nil {
+	if err != nil || row.DeletedAt.Valid {
 		w.WriteHeader(http.StatusNotFound)
 		return
 	}
 
-	if row.UserID != userID {
+	if !isChirpAuthor(row, userID) {
 		w.WriteHeader(http.StatusForbidden)
 		return
 	}
 
-	err = cfg.DB.DeleteChirp(r.Context(), row.ID)
+	err = cfg.deleteChirp(r.Context(), row.ID)
 	if err != nil {
+		log.Printf("failed to delete chirp: %s", err)
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// deleteChirp removes a chirp. A chirp with replies is turned into a
+// tombstone instead, so its thread stays in one piece; the tombstone goes
+// away with its last reply.
+func (cfg *ApiConfig) deleteChirp(ctx context.Context, id uuid.UUID) error {
+	deleted, err := cfg.DB.DeleteChirpWithoutReplies(ctx, id)
+	if err != nil || deleted > 0 {
+		return err
+	}
+
+	_, err = cfg.DB.TombstoneChirp(ctx, database.TombstoneChirpParams{
+		ID:        id,
+		DeletedAt: sql.NullTime{Valid: true, Time: time.Now()},
+	})
+	return err
+}
+
+// isChirpAuthor reports whether userID wrote the chirp. Tombstones left by
+// deleted accounts have no author.
+func isChirpAuthor(row database.Chirp, userID uuid.UUID) bool {
+	return row.UserID.Valid && row.UserID.UUID == userID
+}
+
+func chirpFromRow(row database.Chirp) Chirp {
+	chirp := Chirp{
+		ID:         row.ID,
+		CreatedAt:  row.CreatedAt,
+		UpdatedAt:  row.UpdatedAt,
+		Body:       row.Body,
+		Status:     row.Status,
+		ReplyCount: row.ReplyCount,
+	}
+	if row.UserID.Valid {
+		chirp.UserID = &row.UserID.UUID
+	}
+	if row.InReplyTo.Valid {
+		chirp.InReplyTo = &row.InReplyTo.UUID
+	}
+	if row.DeletedAt.Valid {
+		chirp.DeletedAt = &row.DeletedAt.Time
+	}
+	return chirp
+}
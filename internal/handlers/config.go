@@ -1,17 +1,63 @@
 package handlers
 
 import (
+	"database/sql"
 	"net/http"
 	"sync/atomic"
+	"time"
 
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
 	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+	"github.com/HellYeahOmg/Chirpy/internal/moderation"
+	"github.com/HellYeahOmg/Chirpy/internal/oidc"
+	"github.com/HellYeahOmg/Chirpy/internal/revocation"
+	"github.com/HellYeahOmg/Chirpy/internal/throttle"
 )
 
 type ApiConfig struct {
 	FileserverHits atomic.Int32
 	DB             *database.Queries
-	JwtSecret      string
+	Conn           *sql.DB // pool behind DB, for changes that need a transaction
+	Keys           *auth.KeySet
+	Passwords      *auth.PasswordHasher
+	// PasswordPolicy is applied whenever a user picks a new password.
+	PasswordPolicy *auth.PasswordPolicy
 	PolkaKey       string
+	Mailer         mail.Mailer
+	// BaseURL is the public address of the server, used in links we mail.
+	BaseURL string
+	// Accounts start out unverified; these decide what they may do until the
+	// user follows the link we mailed them.
+	RequireVerifiedEmailToLogin bool
+	RequireVerifiedEmailToChirp bool
+	LoginEmailLimiter           *throttle.Limiter
+	LoginIPLimiter              *throttle.Limiter
+	// MagicLinkLimiter counts magic link requests per email address.
+	MagicLinkLimiter *throttle.Limiter
+	// TrustedProxies is how many proxies in front of us append the client
+	// address to X-Forwarded-For. With 0 the header is ignored.
+	TrustedProxies int
+	// LoginAttemptRetention is how long login attempts are kept for admins
+	// to look at.
+	LoginAttemptRetention time.Duration
+	// OIDC is the external identity provider users may log in with, or nil
+	// when none is configured.
+	OIDC *oidc.Provider
+	// DeletionGracePeriod is how long a deleted account can still be
+	// restored by logging in.
+	DeletionGracePeriod time.Duration
+	// Revocations holds access tokens revoked before they expired. Keys
+	// consults the same list when validating tokens.
+	Revocations *revocation.List
+	// Moderation filters the body of new and edited chirps.
+	Moderation *moderation.List
+	// ChirpEditWindow is how long after posting a chirp its author can
+	// still edit it.
+	ChirpEditWindow time.Duration
+	// DataExportRetention is how long a finished data export can be
+	// downloaded before it is deleted.
+	DataExportRetention time.Duration
 }
 
 func (cfg *ApiConfig) ResetMetricsInc() {
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+)
+
+// sessionModeHeader lets a client ask for the refresh token as a cookie
+// instead of in the response body by sending "X-Session-Mode: cookie" with
+// any request that logs in.
+const sessionModeHeader = "X-Session-Mode"
+
+func wantsCookieSession(r *http.Request) bool {
+	return r.Header.Get(sessionModeHeader) == "cookie"
+}
+
+// setSessionCookies stores the refresh token in an HttpOnly cookie that is
+// only sent to /api, and hands out a fresh CSRF token in a cookie that the
+// client's scripts can read. The CSRF token is returned as well so the
+// client doesn't have to parse cookies.
+func setSessionCookies(w http.ResponseWriter, refreshToken string) (string, error) {
+	csrfToken, err := auth.MakeCSRFToken()
+	if err != nil {
+		return "", err
+	}
+
+	setRefreshTokenCookie(w, refreshToken)
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.CSRFCookie,
+		Value:    csrfToken,
+		Path:     "/",
+		MaxAge:   int(refreshTokenTTL / time.Second),
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+
+	return csrfToken, nil
+}
+
+// setRefreshTokenCookie replaces the refresh token after rotation. The CSRF
+// token stays the same, so other tabs holding it keep working.
+func setRefreshTokenCookie(w http.ResponseWriter, refreshToken string) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.RefreshTokenCookie,
+		Value:    refreshToken,
+		Path:     "/api",
+		MaxAge:   int(refreshTokenTTL / time.Second),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+func clearSessionCookies(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.RefreshTokenCookie,
+		Path:     "/api",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+	http.SetCookie(w, &http.Cookie{
+		Name:     auth.CSRFCookie,
+		Path:     "/",
+		MaxAge:   -1,
+		Secure:   true,
+		SameSite: http.SameSiteStrictMode,
+	})
+}
+
+var errCSRF = errors.New("csrf check failed")
+
+// getRefreshToken reads the refresh token from the Authorization header or,
+// for browser clients, from the cookie. The cookie is sent by the browser on
+// its own, so it is only accepted together with a matching CSRF token.
+func getRefreshToken(r *http.Request) (token string, fromCookie bool, err error) {
+	token, err = auth.GetBearerToken(r.Header)
+	if err == nil {
+		return token, false, nil
+	}
+
+	token, err = auth.GetRefreshTokenCookie(r)
+	if err != nil {
+		return "", false, err
+	}
+
+	if auth.CheckCSRF(r) != nil {
+		return "", true, errCSRF
+	}
+
+	return token, true, nil
+}
+
+// writeRefreshTokenError answers a request whose refresh token couldn't be
+// read.
+func writeRefreshTokenError(w http.ResponseWriter, err error) {
+	if errors.Is(err, errCSRF) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Missing or invalid CSRF token"))
+		return
+	}
+
+	w.WriteHeader(http.StatusUnauthorized)
+}
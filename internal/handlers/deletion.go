@@ -0,0 +1,242 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/account"
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+	"github.com/google/uuid"
+)
+
+// accountDeletionTokenTTL is how long a mailed deletion confirmation token
+// can be used.
+const accountDeletionTokenTTL = 15 * time.Minute
+
+// HandleRequestAccountDeletionToken mails the caller a token that confirms
+// the deletion of their account in place of the password. Accounts created
+// through an identity provider or only ever used with magic links have no
+// password to give.
+func (cfg *ApiConfig) HandleRequestAccountDeletionToken(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	user, err := cfg.DB.GetUser(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	token, err := cfg.createOneTimeToken(r.Context(), tokenPurposeAccountDeletion, user.ID, "", accountDeletionTokenTTL)
+	if err != nil {
+		log.Printf("failed to create account deletion token: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "Confirm the deletion of your Chirpy account",
+		Body: fmt.Sprintf("Someone asked to delete your Chirpy account.\n\n"+
+			"Use this token within 15 minutes to confirm: %s\n\n"+
+			"If it wasn't you, you can ignore this email, but change your password.", token),
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleDeleteAccount schedules the caller's account for deletion once the
+// grace period has passed. It asks for the password again, or a token from
+// HandleRequestAccountDeletionToken, and a second factor when 2FA is on,
+// since an access token alone shouldn't be enough to get rid of an account.
+// All sessions are logged out; logging in again before the deletion happens
+// cancels it.
+func (cfg *ApiConfig) HandleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Password     string `json:"password"`
+		Token        string `json:"token"`
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	type response struct {
+		DeletionScheduledAt time.Time `json:"deletion_scheduled_at"`
+	}
+
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := cfg.DB.GetUser(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	confirmation, err := account.Deletion{Password: params.Password, Token: params.Token}.Confirmation()
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if !cfg.checkLoginThrottle(w, r, user.Email) {
+		return
+	}
+
+	if confirmation == account.ConfirmWithToken {
+		token, err := cfg.DB.ConsumeOneTimeToken(r.Context(), database.ConsumeOneTimeTokenParams{
+			UsedAt:    sql.NullTime{Valid: true, Time: time.Now()},
+			TokenHash: auth.HashToken(params.Token),
+			Purpose:   tokenPurposeAccountDeletion,
+		})
+		if err != nil || token.UserID != userID {
+			cfg.recordLoginAttempt(r, user.Email, false)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Invalid or expired token"))
+			return
+		}
+	} else if cfg.Passwords.Check(params.Password, user.HashedPassword) != nil {
+		cfg.recordLoginAttempt(r, user.Email, false)
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte("Incorrect password"))
+		return
+	}
+
+	totp, err := cfg.DB.GetUserTOTP(r.Context(), userID)
+	if err == nil && totp.ConfirmedAt.Valid {
+		ok, err := cfg.verifySecondFactor(r, userID, params.Code, params.RecoveryCode)
+		if err != nil {
+			log.Printf("failed to verify second factor: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		if !ok {
+			cfg.recordLoginAttempt(r, user.Email, false)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Incorrect code"))
+			return
+		}
+	}
+
+	now := time.Now()
+	scheduledAt := account.DeletionDue(now, cfg.DeletionGracePeriod)
+	_, err = cfg.DB.SetUserDeletion(r.Context(), database.SetUserDeletionParams{
+		DeletionScheduledAt: sql.NullTime{Valid: true, Time: scheduledAt},
+		UpdatedAt:           now,
+		ID:                  userID,
+	})
+	if err != nil {
+		log.Printf("failed to schedule account deletion: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	err = cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: now},
+		UserID:    userID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke sessions of deleted account: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.revokeUserAccessTokens(r, userID)
+
+	data, err := json.Marshal(response{DeletionScheduledAt: scheduledAt})
+	if err != nil {
+		log.Printf("failed to marshal deletion response: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+	w.Write(data)
+}
+
+// cancelAccountDeletion is called for every login: a user who comes back
+// during the grace period keeps their account.
+func (cfg *ApiConfig) cancelAccountDeletion(r *http.Request, user database.User) {
+	if !user.DeletionScheduledAt.Valid {
+		return
+	}
+
+	_, err := cfg.DB.SetUserDeletion(r.Context(), database.SetUserDeletionParams{
+		DeletionScheduledAt: sql.NullTime{},
+		UpdatedAt:           time.Now(),
+		ID:                  user.ID,
+	})
+	if err != nil {
+		log.Printf("failed to cancel deletion of user %s: %s", user.ID, err)
+	}
+}
+
+// RunAccountPurger deletes accounts whose grace period is over every
+// interval until ctx is done. Their chirps, tokens and everything else that
+// belongs to them go with them through ON DELETE CASCADE, except for chirps
+// that other users replied to: those are kept as tombstones without an
+// author, as deleteChirp would, so the replies stay in their thread.
+func (cfg *ApiConfig) RunAccountPurger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		deleted, err := cfg.purgeScheduledUsers(ctx, time.Now())
+		if err != nil {
+			log.Printf("failed to purge deleted accounts: %s", err)
+			continue
+		}
+
+		for _, id := range deleted {
+			log.Printf("deleted account %s after its grace period", id)
+		}
+	}
+}
+
+// purgeScheduledUsers deletes the accounts due at now in one transaction,
+// so the tombstones are only left behind by accounts that are really gone.
+func (cfg *ApiConfig) purgeScheduledUsers(ctx context.Context, now time.Time) ([]uuid.UUID, error) {
+	tx, err := cfg.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	qtx := cfg.DB.WithTx(tx)
+	scheduledBefore := sql.NullTime{Valid: true, Time: now}
+
+	err = qtx.DetachScheduledUsersChirps(ctx, database.DetachScheduledUsersChirpsParams{
+		DeletionScheduledAt: scheduledBefore,
+		Now:                 now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	deleted, err := qtx.DeleteScheduledUsers(ctx, scheduledBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	return deleted, tx.Commit()
+}
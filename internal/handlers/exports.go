@@ -0,0 +1,392 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/export"
+	"github.com/google/uuid"
+)
+
+const (
+	dataExportStatusPending = "pending"
+	dataExportStatusReady   = "ready"
+	dataExportStatusExpired = "expired"
+
+	// dataExportLinkTTL is how long a download link handed out while polling
+	// stays valid. Polling again gives a fresh one; expired links are
+	// deleted by RunOneTimeTokenPruner.
+	dataExportLinkTTL = time.Hour
+	// dataExportBuildTimeout is how long an export may stay pending. Builds
+	// run in the background of one instance, so a restart loses them; an
+	// export pending for longer is taken to be lost and marked failed.
+	dataExportBuildTimeout = 15 * time.Minute
+	// dataExportLoginAttempts caps how much of the login history goes into
+	// an archive.
+	dataExportLoginAttempts = 1000
+)
+
+// HandleCreateDataExport starts building an archive of everything we hold
+// about the caller. The archive is built in the background; the response
+// tells the client which export to poll. While an export is still being
+// built, asking again returns that one instead of starting another.
+func (cfg *ApiConfig) HandleCreateDataExport(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+	now := time.Now()
+
+	err := cfg.DB.DeleteExpiredDataExports(r.Context(), now)
+	if err != nil {
+		log.Printf("failed to delete expired data exports: %s", err)
+	}
+
+	// A lost build would otherwise keep the user from starting a new one.
+	cfg.failStaleDataExports(r.Context(), now)
+
+	exportID, err := cfg.DB.GetPendingDataExport(r.Context(), userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		exportID = uuid.New()
+		err = cfg.DB.CreateDataExport(r.Context(), database.CreateDataExportParams{
+			ID:        exportID,
+			UserID:    userID,
+			CreatedAt: now,
+			ExpiresAt: now.Add(cfg.DataExportRetention),
+		})
+		if err == nil {
+			go cfg.buildDataExport(exportID, userID)
+		}
+	}
+	if err != nil {
+		log.Printf("failed to start data export: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.respondWithDataExport(w, r, exportID, userID, http.StatusAccepted)
+}
+
+// HandleGetDataExport reports the status of an export. Once it is ready the
+// response carries a short-lived download link.
+func (cfg *ApiConfig) HandleGetDataExport(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	exportID, err := uuid.Parse(r.PathValue("exportId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cfg.failStaleDataExports(r.Context(), time.Now())
+	cfg.respondWithDataExport(w, r, exportID, userID, http.StatusOK)
+}
+
+func (cfg *ApiConfig) failStaleDataExports(ctx context.Context, now time.Time) {
+	err := cfg.DB.FailStaleDataExports(ctx, database.FailStaleDataExportsParams{
+		CompletedAt:   sql.NullTime{Valid: true, Time: now},
+		StartedBefore: now.Add(-dataExportBuildTimeout),
+	})
+	if err != nil {
+		log.Printf("failed to fail stale data exports: %s", err)
+	}
+}
+
+// HandleDownloadDataExport serves the archive. It is authenticated by the
+// token in the link rather than by an access token, so the link can be
+// opened straight in a browser.
+func (cfg *ApiConfig) HandleDownloadDataExport(w http.ResponseWriter, r *http.Request) {
+	exportID, err := uuid.Parse(r.PathValue("exportId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	token, err := cfg.DB.GetOneTimeToken(r.Context(), database.GetOneTimeTokenParams{
+		TokenHash: auth.HashToken(r.URL.Query().Get("token")),
+		Purpose:   tokenPurposeDataExport,
+		ExpiresAt: now,
+	})
+	if err != nil || token.Payload != exportID.String() {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	archive, err := cfg.DB.GetDataExportArchive(r.Context(), database.GetDataExportArchiveParams{
+		ID:        exportID,
+		ExpiresAt: now,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get data export archive: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/zip")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=\"chirpy-export-%s.zip\"", now.Format("2006-01-02")))
+	w.Header().Set("Content-Length", strconv.Itoa(len(archive)))
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	w.Write(archive)
+}
+
+func (cfg *ApiConfig) respondWithDataExport(w http.ResponseWriter, r *http.Request, exportID, userID uuid.UUID, status int) {
+	row, err := cfg.DB.GetDataExport(r.Context(), database.GetDataExportParams{
+		ID:     exportID,
+		UserID: userID,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to get data export: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	responseBody := DataExport{
+		ID:        row.ID,
+		Status:    row.Status,
+		CreatedAt: row.CreatedAt,
+		ExpiresAt: row.ExpiresAt,
+	}
+	if row.CompletedAt.Valid {
+		responseBody.CompletedAt = &row.CompletedAt.Time
+	}
+
+	now := time.Now()
+	if !now.Before(row.ExpiresAt) {
+		responseBody.Status = dataExportStatusExpired
+	} else if row.Status == dataExportStatusReady {
+		ttl := min(dataExportLinkTTL, row.ExpiresAt.Sub(now))
+		token, err := cfg.createOneTimeToken(r.Context(), tokenPurposeDataExport, userID, row.ID.String(), ttl)
+		if err != nil {
+			log.Printf("failed to create data export link: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+		responseBody.DownloadURL = fmt.Sprintf("%s/api/exports/%s/download?token=%s", cfg.BaseURL, row.ID, url.QueryEscape(token))
+	}
+
+	data, err := json.Marshal(responseBody)
+	if err != nil {
+		log.Printf("failed to marshal data export: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// buildDataExport runs outside of any request, so it uses its own context.
+func (cfg *ApiConfig) buildDataExport(exportID, userID uuid.UUID) {
+	ctx := context.Background()
+
+	var buf bytes.Buffer
+	err := cfg.writeDataExport(ctx, &buf, userID)
+	now := time.Now()
+	if err != nil {
+		log.Printf("failed to build data export %s: %s", exportID, err)
+		err = cfg.DB.FailDataExport(ctx, database.FailDataExportParams{
+			CompletedAt: sql.NullTime{Valid: true, Time: now},
+			ID:          exportID,
+		})
+		if err != nil {
+			log.Printf("failed to mark data export %s as failed: %s", exportID, err)
+		}
+		return
+	}
+
+	err = cfg.DB.CompleteDataExport(ctx, database.CompleteDataExportParams{
+		Archive:     buf.Bytes(),
+		CompletedAt: sql.NullTime{Valid: true, Time: now},
+		ExpiresAt:   now.Add(cfg.DataExportRetention),
+		ID:          exportID,
+	})
+	if err != nil {
+		log.Printf("failed to store data export %s: %s", exportID, err)
+	}
+}
+
+// writeDataExport writes the archive: the profile as JSON, and every list
+// of records as both JSON and CSV.
+func (cfg *ApiConfig) writeDataExport(ctx context.Context, buf *bytes.Buffer, userID uuid.UUID) error {
+	type profile struct {
+		User
+		Roles               []string   `json:"roles"`
+		DeletionScheduledAt *time.Time `json:"deletion_scheduled_at"`
+	}
+
+	type sessionRecord struct {
+		ID         uuid.UUID  `json:"id"`
+		CreatedAt  time.Time  `json:"created_at"`
+		LastUsedAt time.Time  `json:"last_used_at"`
+		ExpiresAt  time.Time  `json:"expires_at"`
+		RevokedAt  *time.Time `json:"revoked_at"`
+		UserAgent  string     `json:"user_agent"`
+		IPAddress  string     `json:"ip_address"`
+	}
+
+	type subscriptionEvent struct {
+		Event     string    `json:"event"`
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	user, err := cfg.DB.GetUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	roles, err := cfg.DB.GetUserRoles(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	archive := export.NewArchive(buf, time.Now())
+
+	p := profile{
+		User: User{
+			ID:            user.ID,
+			CreatedAt:     user.CreatedAt,
+			UpdatedAt:     user.UpdatedAt,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerifiedAt.Valid,
+			IsChirpyRed:   user.IsChirpyRed.Bool,
+		},
+		Roles: append([]string{}, roles...),
+	}
+	if user.DeletionScheduledAt.Valid {
+		p.DeletionScheduledAt = &user.DeletionScheduledAt.Time
+	}
+	err = archive.AddJSON("profile.json", p)
+	if err != nil {
+		return err
+	}
+
+	chirpRows, err := cfg.DB.ListUserChirps(ctx, uuid.NullUUID{Valid: true, UUID: userID})
+	if err != nil {
+		return err
+	}
+	chirps := []Chirp{}
+	chirpCSV := [][]string{}
+	for _, row := range chirpRows {
+		chirps = append(chirps, chirpFromRow(row))
+		chirpCSV = append(chirpCSV, []string{
+			row.ID.String(), export.FormatTime(row.CreatedAt), export.FormatTime(row.UpdatedAt), row.Body, row.Status,
+		})
+	}
+	err = archive.AddTable("chirps", chirps, []string{"id", "created_at", "updated_at", "body", "status"}, chirpCSV)
+	if err != nil {
+		return err
+	}
+
+	sessionRows, err := cfg.DB.ListUserRefreshTokens(ctx, userID)
+	if err != nil {
+		return err
+	}
+	sessions := []sessionRecord{}
+	sessionCSV := [][]string{}
+	for _, row := range sessionRows {
+		session := sessionRecord{
+			ID:         row.FamilyID,
+			CreatedAt:  row.CreatedAt,
+			LastUsedAt: row.LastUsedAt,
+			ExpiresAt:  row.ExperiesAt,
+			UserAgent:  row.UserAgent,
+			IPAddress:  row.IpAddress,
+		}
+		if row.RevokedAt.Valid {
+			session.RevokedAt = &row.RevokedAt.Time
+		}
+		sessions = append(sessions, session)
+		sessionCSV = append(sessionCSV, []string{
+			row.FamilyID.String(), export.FormatTime(row.CreatedAt), export.FormatTime(row.LastUsedAt),
+			export.FormatTime(row.ExperiesAt), export.FormatTime(row.RevokedAt.Time), row.UserAgent, row.IpAddress,
+		})
+	}
+	err = archive.AddTable("sessions", sessions,
+		[]string{"id", "created_at", "last_used_at", "expires_at", "revoked_at", "user_agent", "ip_address"}, sessionCSV)
+	if err != nil {
+		return err
+	}
+
+	eventRows, err := cfg.DB.ListSubscriptionEvents(ctx, userID)
+	if err != nil {
+		return err
+	}
+	events := []subscriptionEvent{}
+	eventCSV := [][]string{}
+	for _, row := range eventRows {
+		events = append(events, subscriptionEvent{Event: row.Event, CreatedAt: row.CreatedAt})
+		eventCSV = append(eventCSV, []string{row.Event, export.FormatTime(row.CreatedAt)})
+	}
+	err = archive.AddTable("subscription_history", events, []string{"event", "created_at"}, eventCSV)
+	if err != nil {
+		return err
+	}
+
+	keyRows, err := cfg.DB.ListAPIKeys(ctx, userID)
+	if err != nil {
+		return err
+	}
+	keys := []APIKey{}
+	keyCSV := [][]string{}
+	for _, row := range keyRows {
+		keys = append(keys, newAPIKey(row))
+		keyCSV = append(keyCSV, []string{
+			row.ID.String(), row.Name, row.Prefix, strings.Join(row.Scopes, " "), export.FormatTime(row.CreatedAt),
+			export.FormatTime(row.ExpiresAt.Time), export.FormatTime(row.LastUsedAt.Time),
+		})
+	}
+	err = archive.AddTable("api_keys", keys,
+		[]string{"id", "name", "prefix", "scopes", "created_at", "expires_at", "last_used_at"}, keyCSV)
+	if err != nil {
+		return err
+	}
+
+	attemptRows, err := cfg.DB.ListLoginAttempts(ctx, database.ListLoginAttemptsParams{
+		Email:    sql.NullString{Valid: true, String: user.Email},
+		RowLimit: dataExportLoginAttempts,
+	})
+	if err != nil {
+		return err
+	}
+	attempts := []LoginAttempt{}
+	attemptCSV := [][]string{}
+	for _, row := range attemptRows {
+		attempts = append(attempts, LoginAttempt{
+			ID:        row.ID,
+			Email:     row.Email,
+			IPAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			Succeeded: row.Succeeded,
+			CreatedAt: row.CreatedAt,
+		})
+		attemptCSV = append(attemptCSV, []string{
+			row.ID.String(), export.FormatTime(row.CreatedAt), row.IpAddress, row.UserAgent, strconv.FormatBool(row.Succeeded),
+		})
+	}
+	err = archive.AddTable("login_attempts", attempts,
+		[]string{"id", "created_at", "ip_address", "user_agent", "succeeded"}, attemptCSV)
+	if err != nil {
+		return err
+	}
+
+	return archive.Close()
+}
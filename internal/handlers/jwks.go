@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+)
+
+func (cfg *ApiConfig) HandleJWKS(w http.ResponseWriter, r *http.Request) {
+	data, err := json.Marshal(cfg.Keys.JWKS())
+	if err != nil {
+		log.Printf("failed to marshal jwks: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,152 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/account"
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+)
+
+const magicLinkTTL = 15 * time.Minute
+
+func magicLinkEmailKey(email string) string {
+	return "magic-link-email:" + strings.ToLower(email)
+}
+
+// HandleRequestMagicLink mails a single-use login link. Like a password
+// reset it answers 202 whether or not the account exists. Every request
+// counts against the address, so it can't be used to flood an inbox.
+func (cfg *ApiConfig) HandleRequestMagicLink(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email string `json:"email"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil || params.Email == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	key := magicLinkEmailKey(params.Email)
+	wait, err := cfg.MagicLinkLimiter.Check(r.Context(), key)
+	if err != nil {
+		log.Printf("failed to check magic link throttle: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+	if wait > 0 {
+		writeTooManyRequests(w, wait)
+		return
+	}
+
+	err = cfg.MagicLinkLimiter.Fail(r.Context(), key)
+	if err != nil {
+		log.Printf("failed to update magic link throttle: %s", err)
+	}
+
+	user, err := cfg.DB.GetUserByEmail(r.Context(), params.Email)
+	if err != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	token, err := cfg.createOneTimeToken(r.Context(), tokenPurposeMagicLink, user.ID, user.Email, magicLinkTTL)
+	if err != nil {
+		log.Printf("failed to create magic link token: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	link := fmt.Sprintf("%s/app/magic-login?token=%s", cfg.BaseURL, url.QueryEscape(token))
+	cfg.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "Your Chirpy login link",
+		Body: fmt.Sprintf("Follow this link within 15 minutes to log in to Chirpy:\n%s\n\n"+
+			"Or use this token: %s\n\n"+
+			"If you didn't ask to log in, you can ignore this email.", link, token),
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+// HandleConsumeMagicLink logs the user in with a token from a magic link and
+// answers like HandleLogin. Following the link also proves the user owns the
+// address it was sent to.
+func (cfg *ApiConfig) HandleConsumeMagicLink(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token string `json:"token"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	token, err := cfg.DB.ConsumeOneTimeToken(r.Context(), database.ConsumeOneTimeTokenParams{
+		UsedAt:    sql.NullTime{Valid: true, Time: now},
+		TokenHash: auth.HashToken(params.Token),
+		Purpose:   tokenPurposeMagicLink,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Invalid or expired token"))
+		return
+	}
+
+	user, err := cfg.DB.GetUser(r.Context(), token.UserID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if err := account.CheckMagicLink(user.Email, token.Payload); err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = cfg.DB.InvalidateOneTimeTokens(r.Context(), database.InvalidateOneTimeTokensParams{
+		UsedAt:  sql.NullTime{Valid: true, Time: now},
+		UserID:  user.ID,
+		Purpose: tokenPurposeMagicLink,
+	})
+	if err != nil {
+		log.Printf("failed to invalidate magic link tokens: %s", err)
+	}
+
+	if !user.EmailVerifiedAt.Valid {
+		verifiedAt := sql.NullTime{Valid: true, Time: now}
+		_, err = cfg.DB.MarkEmailVerified(r.Context(), database.MarkEmailVerifiedParams{
+			EmailVerifiedAt: verifiedAt,
+			ID:              user.ID,
+			Email:           user.Email,
+		})
+		if err != nil {
+			log.Printf("failed to mark email as verified: %s", err)
+		} else {
+			user.EmailVerifiedAt = verifiedAt
+		}
+	}
+
+	err = cfg.MagicLinkLimiter.Succeed(r.Context(), magicLinkEmailKey(user.Email))
+	if err != nil {
+		log.Printf("failed to update magic link throttle: %s", err)
+	}
+
+	cfg.finishLogin(w, r, user)
+}
@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+)
+
+// sendMail delivers msg in the background. Handlers answer the same way
+// whether or not a mail was sent, so delivery must not affect their timing.
+func (cfg *ApiConfig) sendMail(msg mail.Message) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		err := cfg.Mailer.Send(ctx, msg)
+		if err != nil {
+			log.Printf("failed to send %q mail: %s", msg.Subject, err)
+		}
+	}()
+}
@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/moderation"
+	"github.com/google/uuid"
+)
+
+// Chirps containing a word with the hold action wait for a moderator before
+// anyone but their author can see them.
+const (
+	chirpStatusPublished = "published"
+	chirpStatusHeld      = "held"
+)
+
+func canSeeHeldChirp(r *http.Request, row database.Chirp) bool {
+	principal, ok := auth.PrincipalFromContext(r.Context())
+	if !ok {
+		return false
+	}
+	return isChirpAuthor(row, principal.UserID) || principal.HasRole(auth.RoleModerator)
+}
+
+func (cfg *ApiConfig) HandleListModerationWords(w http.ResponseWriter, r *http.Request) {
+	rows, err := cfg.DB.ListModerationWords(r.Context())
+	if err != nil {
+		log.Printf("failed to list moderation words: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := []ModerationWord{}
+	for _, row := range rows {
+		result = append(result, ModerationWord{
+			Word:      row.Word,
+			Action:    row.Action,
+			CreatedAt: row.CreatedAt,
+			UpdatedAt: row.UpdatedAt,
+		})
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal moderation words: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// HandlePutModerationWord adds a word to the list or changes its action.
+// Words are stored normalized, so "F0rnax" and "fornax" are the same entry.
+func (cfg *ApiConfig) HandlePutModerationWord(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Action string `json:"action"`
+	}
+
+	word := moderation.Normalize(r.PathValue("word"))
+	if word == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("A word needs at least one letter"))
+		return
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	action, err := moderation.ParseAction(params.Action)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	row, err := cfg.DB.UpsertModerationWord(r.Context(), database.UpsertModerationWordParams{
+		Word:      word,
+		Action:    string(action),
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to store moderation word: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.reloadModeration(r)
+
+	data, err := json.Marshal(ModerationWord{
+		Word:      row.Word,
+		Action:    row.Action,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	})
+	if err != nil {
+		log.Printf("failed to marshal moderation word: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (cfg *ApiConfig) HandleDeleteModerationWord(w http.ResponseWriter, r *http.Request) {
+	deleted, err := cfg.DB.DeleteModerationWord(r.Context(), moderation.Normalize(r.PathValue("word")))
+	if err != nil {
+		log.Printf("failed to delete moderation word: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if deleted == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	cfg.reloadModeration(r)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// reloadModeration applies a change to the word list on this instance right
+// away; other instances pick it up on their next reload.
+func (cfg *ApiConfig) reloadModeration(r *http.Request) {
+	err := cfg.Moderation.Load(r.Context())
+	if err != nil {
+		log.Printf("failed to reload moderation words: %s", err)
+	}
+}
+
+func (cfg *ApiConfig) HandleListHeldChirps(w http.ResponseWriter, r *http.Request) {
+	rows, err := cfg.DB.ListHeldChirps(r.Context())
+	if err != nil {
+		log.Printf("failed to list held chirps: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := []Chirp{}
+	for _, row := range rows {
+		result = append(result, chirpFromRow(row))
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal held chirps: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// HandleApproveChirp publishes a held chirp.
+func (cfg *ApiConfig) HandleApproveChirp(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	updated, err := cfg.DB.SetChirpStatus(r.Context(), database.SetChirpStatusParams{
+		Status: chirpStatusPublished,
+		ID:     chirpID,
+	})
+	if err != nil {
+		log.Printf("failed to approve chirp: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if updated == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleRejectChirp deletes a held chirp.
+func (cfg *ApiConfig) HandleRejectChirp(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	row, err := cfg.DB.GetChirp(r.Context(), chirpID)
+	if err != nil || row.Status != chirpStatusHeld {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	err = cfg.deleteChirp(r.Context(), row.ID)
+	if err != nil {
+		log.Printf("failed to reject chirp: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
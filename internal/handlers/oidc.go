@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"context"
+	"crypto/subtle"
+	"database/sql"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/oidc"
+)
+
+const oidcStateTTL = 10 * time.Minute
+
+// oidcStateCookie ties a login to the browser that started it. Without it,
+// anyone could send a victim a callback link with their own code and state,
+// and log the victim into the attacker's account.
+const oidcStateCookie = "chirpy_oidc_state"
+
+// unsetPassword matches the default of users.hashed_password. No password
+// hashes to it, so accounts created through an identity provider can't log
+// in with a password until the user resets it.
+const unsetPassword = "unset"
+
+var errIdentityConflict = errors.New("email belongs to another account")
+
+// HandleOIDCLogin starts an authorization code flow with PKCE by sending the
+// user to the identity provider.
+func (cfg *ApiConfig) HandleOIDCLogin(w http.ResponseWriter, r *http.Request) {
+	if cfg.OIDC == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	now := time.Now()
+	err := cfg.DB.DeleteOIDCStatesBefore(r.Context(), now)
+	if err != nil {
+		log.Printf("failed to delete expired oidc states: %s", err)
+	}
+
+	state, err := oidc.RandomString()
+	if err != nil {
+		log.Printf("failed to create oidc state: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	nonce, err := oidc.RandomString()
+	if err != nil {
+		log.Printf("failed to create oidc nonce: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	verifier, challenge, err := oidc.NewPKCE()
+	if err != nil {
+		log.Printf("failed to create pkce verifier: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	err = cfg.DB.CreateOIDCState(r.Context(), database.CreateOIDCStateParams{
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		CreatedAt:    now,
+		ExpiresAt:    now.Add(oidcStateTTL),
+	})
+	if err != nil {
+		log.Printf("failed to store oidc state: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	// Lax, since the provider sends the browser back with a top-level
+	// navigation from its own site.
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Value:    state,
+		Path:     "/api/login/oidc",
+		MaxAge:   int(oidcStateTTL / time.Second),
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	http.Redirect(w, r, cfg.OIDC.AuthCodeURL(state, nonce, challenge), http.StatusFound)
+}
+
+// HandleOIDCCallback is where the identity provider sends the user back. It
+// links the external identity to a user, creating one if needed, and then
+// logs them in the same way a password login does.
+func (cfg *ApiConfig) HandleOIDCCallback(w http.ResponseWriter, r *http.Request) {
+	if cfg.OIDC == nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	query := r.URL.Query()
+	if providerErr := query.Get("error"); providerErr != "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Identity provider returned " + providerErr))
+		return
+	}
+
+	cookie, err := r.Cookie(oidcStateCookie)
+	http.SetCookie(w, &http.Cookie{
+		Name:     oidcStateCookie,
+		Path:     "/api/login/oidc",
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	if err != nil || subtle.ConstantTimeCompare([]byte(cookie.Value), []byte(query.Get("state"))) != 1 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Login was not started from this browser"))
+		return
+	}
+
+	state, err := cfg.DB.ConsumeOIDCState(r.Context(), database.ConsumeOIDCStateParams{
+		State:     query.Get("state"),
+		ExpiresAt: time.Now(),
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Invalid or expired state"))
+		return
+	}
+
+	rawIDToken, err := cfg.OIDC.Exchange(r.Context(), query.Get("code"), state.CodeVerifier)
+	if err != nil {
+		log.Printf("failed to exchange oidc code: %s", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	idToken, err := cfg.OIDC.VerifyIDToken(r.Context(), rawIDToken, state.Nonce)
+	if err != nil {
+		log.Printf("failed to verify id token: %s", err)
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	user, err := cfg.userForIdentity(r.Context(), idToken)
+	if errors.Is(err, errIdentityConflict) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("An account with this email already exists; log in with your password"))
+		return
+	}
+	if err != nil {
+		log.Printf("failed to link oidc identity: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.finishLogin(w, r, user)
+}
+
+// userForIdentity returns the user linked to an external identity. Unknown
+// identities are linked to the user with the same email, but only when the
+// provider vouches for that email; otherwise a new user is created.
+func (cfg *ApiConfig) userForIdentity(ctx context.Context, idToken *oidc.IDToken) (database.User, error) {
+	identity, err := cfg.DB.GetUserIdentity(ctx, database.GetUserIdentityParams{
+		Issuer:  idToken.Issuer,
+		Subject: idToken.Subject,
+	})
+	if err == nil {
+		return cfg.DB.GetUser(ctx, identity.UserID)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return database.User{}, err
+	}
+
+	if idToken.Email == "" {
+		return database.User{}, errors.New("id token has no email")
+	}
+
+	user, err := cfg.DB.GetUserByEmail(ctx, idToken.Email)
+	if err == nil && !idToken.EmailVerified {
+		return database.User{}, errIdentityConflict
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		user, err = cfg.DB.CreateUser(ctx, database.CreateUserParams{
+			Email:          idToken.Email,
+			HashedPassword: unsetPassword,
+		})
+	}
+	if err != nil {
+		return database.User{}, err
+	}
+
+	now := time.Now()
+	if idToken.EmailVerified && !user.EmailVerifiedAt.Valid {
+		verifiedAt := sql.NullTime{Valid: true, Time: now}
+		_, err = cfg.DB.MarkEmailVerified(ctx, database.MarkEmailVerifiedParams{
+			EmailVerifiedAt: verifiedAt,
+			ID:              user.ID,
+			Email:           user.Email,
+		})
+		if err != nil {
+			return database.User{}, err
+		}
+		user.EmailVerifiedAt = verifiedAt
+	}
+
+	err = cfg.DB.CreateUserIdentity(ctx, database.CreateUserIdentityParams{
+		Issuer:    idToken.Issuer,
+		Subject:   idToken.Subject,
+		UserID:    user.ID,
+		Email:     idToken.Email,
+		CreatedAt: now,
+	})
+	if err != nil {
+		return database.User{}, err
+	}
+
+	return user, nil
+}
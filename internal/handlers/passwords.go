@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+)
+
+const passwordResetTTL = time.Hour
+
+// HandleRequestPasswordReset mails a reset link to the account. It answers
+// 202 whether or not the email belongs to a user so it can't be used to find
+// out who has an account.
+func (cfg *ApiConfig) HandleRequestPasswordReset(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Email string `json:"email"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	user, err := cfg.DB.GetUserByEmail(r.Context(), params.Email)
+	if err != nil {
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	token, err := cfg.createOneTimeToken(r.Context(), tokenPurposePasswordReset, user.ID, "", passwordResetTTL)
+	if err != nil {
+		log.Printf("failed to create password reset token: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	link := fmt.Sprintf("%s/app/reset-password?token=%s", cfg.BaseURL, url.QueryEscape(token))
+	cfg.sendMail(mail.Message{
+		To:      user.Email,
+		Subject: "Reset your Chirpy password",
+		Body: fmt.Sprintf("Someone asked to reset the password of your Chirpy account.\n\n"+
+			"Follow this link within an hour to choose a new one:\n%s\n\n"+
+			"Or use this token: %s\n\n"+
+			"If it wasn't you, you can ignore this email.", link, token),
+	})
+
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func (cfg *ApiConfig) HandleConfirmPasswordReset(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token    string `json:"token"`
+		Password string `json:"password"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	if params.Password == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Password is required"))
+		return
+	}
+
+	now := time.Now()
+	pending, err := cfg.DB.GetOneTimeToken(r.Context(), database.GetOneTimeTokenParams{
+		TokenHash: auth.HashToken(params.Token),
+		Purpose:   tokenPurposePasswordReset,
+		ExpiresAt: now,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Invalid or expired token"))
+		return
+	}
+
+	user, err := cfg.DB.GetUser(r.Context(), pending.UserID)
+	if err != nil {
+		log.Printf("failed to get user for password reset: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	// The policy is checked before the token is consumed so the user can
+	// retry with a better password using the same link.
+	if !cfg.checkPasswordPolicy(w, params.Password, user.Email) {
+		return
+	}
+
+	token, err := cfg.DB.ConsumeOneTimeToken(r.Context(), database.ConsumeOneTimeTokenParams{
+		UsedAt:    sql.NullTime{Valid: true, Time: now},
+		TokenHash: auth.HashToken(params.Token),
+		Purpose:   tokenPurposePasswordReset,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Invalid or expired token"))
+		return
+	}
+
+	hash, err := cfg.Passwords.Hash(params.Password)
+	if err != nil {
+		log.Printf("failed to hash the password: %v", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	err = cfg.DB.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+		HashedPassword: hash,
+		UpdatedAt:      now,
+		ID:             token.UserID,
+	})
+	if err != nil {
+		log.Printf("failed to update password: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	// Whoever knew the old password may still hold a session, and any other
+	// reset link that is still out there shouldn't work anymore either.
+	err = cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: now},
+		UserID:    token.UserID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke refresh tokens after password reset: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.revokeUserAccessTokens(r, token.UserID)
+
+	err = cfg.DB.InvalidateOneTimeTokens(r.Context(), database.InvalidateOneTimeTokensParams{
+		UsedAt:  sql.NullTime{Valid: true, Time: now},
+		UserID:  token.UserID,
+		Purpose: tokenPurposePasswordReset,
+	})
+	if err != nil {
+		log.Printf("failed to invalidate password reset tokens: %s", err)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// checkPasswordPolicy writes a 400 naming the broken rule and returns false
+// when password isn't acceptable for the account with the given email.
+func (cfg *ApiConfig) checkPasswordPolicy(w http.ResponseWriter, password, email string) bool {
+	type response struct {
+		Error string `json:"error"`
+		Rule  string `json:"rule"`
+	}
+
+	if cfg.PasswordPolicy == nil {
+		return true
+	}
+
+	err := cfg.PasswordPolicy.Check(password, email)
+	if err == nil {
+		return true
+	}
+
+	var violation *auth.PolicyViolation
+	if !errors.As(err, &violation) {
+		log.Printf("failed to check password policy: %s", err)
+		w.WriteHeader(500)
+		return false
+	}
+
+	data, err := json.Marshal(response{Error: violation.Message, Rule: violation.Rule})
+	if err != nil {
+		log.Printf("failed to marshal policy violation: %s", err)
+		w.WriteHeader(500)
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	w.Write(data)
+	return false
+}
@@ -3,7 +3,9 @@ package handlers
 import (
 	"database/sql"
 	"encoding/json"
+	"log"
 	"net/http"
+	"time"
 
 	"github.com/HellYeahOmg/Chirpy/internal/auth"
 	"github.com/HellYeahOmg/Chirpy/internal/database"
@@ -58,5 +60,17 @@ func (cfg *ApiConfig) HandlePolkaWebhook(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	// The history is only kept for data exports; Polka retries on errors, so
+	// failing to record it shouldn't fail the webhook.
+	err = cfg.DB.AddSubscriptionEvent(r.Context(), database.AddSubscriptionEventParams{
+		ID:        uuid.New(),
+		UserID:    parsedUserId,
+		Event:     params.Event,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to record subscription event: %s", err)
+	}
+
 	w.WriteHeader(http.StatusNoContent)
 }
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/chirpedit"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// HandleUpdateChirp lets the author edit a chirp within ChirpEditWindow of
+// posting it. The new body goes through the same checks as a new chirp, and
+// the body it replaces is kept as a revision unless it was held, since held
+// bodies are not for everyone to see.
+func (cfg *ApiConfig) HandleUpdateChirp(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Body string `json:"body"`
+	}
+
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	chirpID, err := uuid.Parse(r.PathValue("chirpId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	row, err := cfg.DB.GetChirp(r.Context(), chirpID)
+	if err != nil || row.DeletedAt.Valid {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if !isChirpAuthor(row, userID) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	body, status, ok := cfg.checkChirpBody(w, params.Body)
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	current := chirpedit.Version{Body: row.Body, Status: row.Status}
+	edited := chirpedit.Version{Body: body, Status: status}
+	switch chirpedit.Check(row.CreatedAt, current, edited, now, cfg.ChirpEditWindow) {
+	case chirpedit.WindowClosed:
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(fmt.Sprintf("Chirps can only be edited within %s of posting", cfg.ChirpEditWindow)))
+		return
+	case chirpedit.Unchanged:
+		// Nothing changed, so there is nothing to keep a revision of.
+		cfg.writeChirp(w, row)
+		return
+	}
+
+	// The update repeats the author and window checks with the same
+	// cutoff, so they still hold if the chirp changed since it was read.
+	updated, err := cfg.DB.EditChirp(r.Context(), database.EditChirpParams{
+		RevisionID:    uuid.New(),
+		UpdatedAt:     now,
+		ID:            row.ID,
+		UserID:        uuid.NullUUID{Valid: true, UUID: userID},
+		EditableAfter: chirpedit.EditableAfter(now, cfg.ChirpEditWindow),
+		Body:          body,
+		Status:        status,
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// The window can't have closed since the check above, so the
+		// chirp was deleted or its author's account purged meanwhile.
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to edit chirp: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.writeChirp(w, updated)
+}
+
+// HandleListChirpRevisions lists the earlier versions of a chirp, most
+// recently replaced first. The current version is the chirp itself.
+func (cfg *ApiConfig) HandleListChirpRevisions(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	row, err := cfg.DB.GetChirp(r.Context(), chirpID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if row.Status == chirpStatusHeld && !canSeeHeldChirp(r, row) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	rows, err := cfg.DB.ListChirpRevisions(r.Context(), row.ID)
+	if err != nil {
+		log.Printf("failed to list chirp revisions: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	revisions := make([]chirpedit.Revision, 0, len(rows))
+	for _, revision := range rows {
+		revisions = append(revisions, chirpedit.Revision{
+			ID:         revision.ID,
+			Body:       revision.Body,
+			CreatedAt:  revision.CreatedAt,
+			ReplacedAt: revision.ReplacedAt,
+		})
+	}
+	chirpedit.SortNewestFirst(revisions)
+
+	result := []ChirpRevision{}
+	for _, revision := range revisions {
+		result = append(result, ChirpRevision(revision))
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal chirp revisions: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (cfg *ApiConfig) writeChirp(w http.ResponseWriter, row database.Chirp) {
+	data, err := json.Marshal(chirpFromRow(row))
+	if err != nil {
+		log.Printf("failed to marshal chirp: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func (cfg *ApiConfig) HandleGetUserRoles(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	roles, err := cfg.DB.GetUserRoles(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to get user roles: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	data, err := json.Marshal(append([]string{string(auth.RoleUser)}, roles...))
+	if err != nil {
+		log.Printf("failed to marshal roles: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (cfg *ApiConfig) HandleGrantRole(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Role string `json:"role"`
+	}
+
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err = decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	role, err := parseGrantableRole(params.Role)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	err = cfg.DB.GrantRole(r.Context(), database.GrantRoleParams{
+		UserID:    userID,
+		Role:      string(role),
+		CreatedAt: time.Now(),
+	})
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if err != nil {
+		log.Printf("failed to grant role: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *ApiConfig) HandleRevokeRole(w http.ResponseWriter, r *http.Request) {
+	userID, err := uuid.Parse(r.PathValue("userId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	role, err := parseGrantableRole(r.PathValue("role"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeRole(r.Context(), database.RevokeRoleParams{
+		UserID: userID,
+		Role:   string(role),
+	})
+	if err != nil {
+		log.Printf("failed to revoke role: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if revoked == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseGrantableRole accepts the roles that are stored per user. RoleUser is
+// implied for everyone and can't be granted or revoked.
+func parseGrantableRole(s string) (auth.Role, error) {
+	role, err := auth.ParseRole(s)
+	if err != nil {
+		return "", err
+	}
+	if role == auth.RoleUser {
+		return "", errors.New("the user role is implicit")
+	}
+	return role, nil
+}
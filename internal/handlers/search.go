@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/HellYeahOmg/Chirpy/internal/chirpquery"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// maxSearchQueryLength keeps search queries within what a person types.
+const maxSearchQueryLength = 256
+
+// HandleSearchChirps runs a full-text search over published chirps, best
+// matches first. q takes web search syntax: "quoted phrases", or, and -word
+// to exclude a word. Pages work like those of HandleGetChirps.
+func (cfg *ApiConfig) HandleSearchChirps(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	q := strings.TrimSpace(query.Get("q"))
+	if q == "" {
+		writeQueryParamError(w, "q", "q is required")
+		return
+	}
+	if len(q) > maxSearchQueryLength {
+		writeQueryParamError(w, "q", fmt.Sprintf("q can be at most %d bytes long", maxSearchQueryLength))
+		return
+	}
+
+	params := database.SearchChirpsParams{Query: q}
+
+	authorIDs, err := chirpquery.ParseAuthorIDs(query["author_id"])
+	if err != nil {
+		writeParamError(w, err)
+		return
+	}
+	params.AuthorIds = authorIDs
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := pagination.DecodeRankCursor(cursor)
+		if err != nil {
+			writeQueryParamError(w, "cursor", "cursor must be a next_cursor returned by this endpoint")
+			return
+		}
+		params.AfterRank = sql.NullFloat64{Valid: true, Float64: float64(after.Rank)}
+		params.AfterCreatedAt = sql.NullTime{Valid: true, Time: after.CreatedAt}
+		params.AfterID = uuid.NullUUID{Valid: true, UUID: after.ID}
+	}
+
+	limit, err := pagination.ParseLimit(query.Get("limit"), chirpquery.DefaultLimit, chirpquery.MaxLimit)
+	if err != nil {
+		writeQueryParamError(w, "limit", err.Error())
+		return
+	}
+	params.RowLimit = int32(limit + 1)
+
+	rows, err := cfg.DB.SearchChirps(r.Context(), params)
+	if err != nil {
+		log.Printf("failed to search chirps: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := ChirpPage{Chirps: []Chirp{}}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		last := rows[len(rows)-1]
+		next := pagination.RankCursor{
+			Rank:   last.Rank,
+			Cursor: pagination.Cursor{CreatedAt: last.CreatedAt, ID: last.ID},
+		}.Encode()
+		result.NextCursor = &next
+	}
+
+	for _, row := range rows {
+		result.Chirps = append(result.Chirps, chirpFromRow(database.Chirp{
+			ID:         row.ID,
+			CreatedAt:  row.CreatedAt,
+			UpdatedAt:  row.UpdatedAt,
+			Body:       row.Body,
+			UserID:     row.UserID,
+			Status:     row.Status,
+			InReplyTo:  row.InReplyTo,
+			ReplyCount: row.ReplyCount,
+			DeletedAt:  row.DeletedAt,
+		}))
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal search results: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+func (cfg *ApiConfig) HandleListSessions(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	rows, err := cfg.DB.ListActiveSessions(r.Context(), database.ListActiveSessionsParams{
+		UserID:     userID,
+		ExperiesAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to list sessions: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := []Session{}
+	for _, row := range rows {
+		result = append(result, Session{
+			ID:         row.FamilyID,
+			CreatedAt:  row.SessionStartedAt,
+			LastUsedAt: row.LastUsedAt,
+			ExpiresAt:  row.ExperiesAt,
+			UserAgent:  row.UserAgent,
+			IPAddress:  row.IpAddress,
+		})
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal sessions: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func (cfg *ApiConfig) HandleRevokeSession(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	revoked, err := cfg.DB.RevokeSession(r.Context(), database.RevokeSessionParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		UserID:    userID,
+		FamilyID:  sessionID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke session: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if revoked == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	cfg.revokeSessionAccessTokens(r, sessionID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleRevokeAllSessions logs the user out everywhere by revoking every
+// refresh token they hold, including the one used by the calling client.
+func (cfg *ApiConfig) HandleRevokeAllSessions(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	err := cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{
+		RevokedAt: sql.NullTime{Valid: true, Time: time.Now()},
+		UserID:    userID,
+	})
+	if err != nil {
+		log.Printf("failed to revoke sessions: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	cfg.revokeUserAccessTokens(r, userID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
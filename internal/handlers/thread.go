@@ -0,0 +1,131 @@
+package handlers
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+
+	"github.com/HellYeahOmg/Chirpy/internal/chirpquery"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// How far a thread reaches above and below the requested chirp. Deeper
+// replies are still reachable by asking for the thread of one of the
+// deepest replies shown.
+const (
+	maxThreadAncestors = 50
+	maxThreadDepth     = 10
+)
+
+// HandleGetChirpThread returns the conversation around a chirp. Only the
+// replies are paginated; each page repeats the ancestors and the chirp.
+func (cfg *ApiConfig) HandleGetChirpThread(w http.ResponseWriter, r *http.Request) {
+	chirpID, err := uuid.Parse(r.PathValue("chirpId"))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	query := r.URL.Query()
+	params := database.GetChirpRepliesParams{ID: chirpID, MaxDepth: maxThreadDepth}
+
+	if cursor := query.Get("cursor"); cursor != "" {
+		after, err := pagination.DecodePathCursor(cursor)
+		if err != nil {
+			writeQueryParamError(w, "cursor", "cursor must be a next_cursor returned by this endpoint")
+			return
+		}
+		params.AfterPath = after
+	}
+
+	limit, err := pagination.ParseLimit(query.Get("limit"), chirpquery.DefaultLimit, chirpquery.MaxLimit)
+	if err != nil {
+		writeQueryParamError(w, "limit", err.Error())
+		return
+	}
+	params.RowLimit = int32(limit + 1)
+
+	row, err := cfg.DB.GetChirp(r.Context(), chirpID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if row.Status == chirpStatusHeld && !canSeeHeldChirp(r, row) {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	ancestors, err := cfg.DB.GetChirpAncestors(r.Context(), database.GetChirpAncestorsParams{
+		ID:       row.ID,
+		MaxDepth: maxThreadAncestors,
+	})
+	if err != nil {
+		log.Printf("failed to get chirp ancestors: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	replies, err := cfg.DB.GetChirpReplies(r.Context(), params)
+	if err != nil {
+		log.Printf("failed to get chirp replies: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := ChirpThread{
+		Ancestors: []Chirp{},
+		Chirp:     chirpFromRow(row),
+		Replies:   []ThreadReply{},
+	}
+
+	for _, ancestor := range ancestors {
+		// A chirp can be held after it was replied to.
+		if ancestor.Status == chirpStatusHeld && !canSeeHeldChirp(r, ancestor) {
+			continue
+		}
+		result.Ancestors = append(result.Ancestors, chirpFromRow(ancestor))
+	}
+
+	if len(replies) > limit {
+		replies = replies[:limit]
+		next := pagination.PathCursor(replies[len(replies)-1].Path).Encode()
+		result.NextCursor = &next
+	}
+
+	for _, reply := range replies {
+		chirp := database.Chirp{
+			ID:         reply.ID,
+			CreatedAt:  reply.CreatedAt,
+			UpdatedAt:  reply.UpdatedAt,
+			Body:       reply.Body,
+			UserID:     reply.UserID,
+			Status:     reply.Status,
+			InReplyTo:  reply.InReplyTo,
+			ReplyCount: reply.ReplyCount,
+			DeletedAt:  reply.DeletedAt,
+		}
+		// A held reply keeps its place so the replies below it stay
+		// connected, but only its author and moderators see its body.
+		if chirp.Status == chirpStatusHeld && !canSeeHeldChirp(r, chirp) {
+			chirp.Body = ""
+			chirp.UserID = uuid.NullUUID{}
+		}
+		result.Replies = append(result.Replies, ThreadReply{
+			Chirp: chirpFromRow(chirp),
+			Depth: reply.Depth,
+		})
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal chirp thread: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
@@ -0,0 +1,167 @@
+package handlers
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/throttle"
+	"github.com/google/uuid"
+)
+
+func loginEmailKey(email string) string {
+	return "login-email:" + strings.ToLower(email)
+}
+
+func loginIPKey(ip string) string {
+	return "login-ip:" + ip
+}
+
+// checkLoginThrottle answers 429 and returns false when either the account
+// or the client address has failed too often and has to wait.
+func (cfg *ApiConfig) checkLoginThrottle(w http.ResponseWriter, r *http.Request, email string) bool {
+	emailWait, err := cfg.LoginEmailLimiter.Check(r.Context(), loginEmailKey(email))
+	if err != nil {
+		log.Printf("failed to check login throttle: %s", err)
+		w.WriteHeader(500)
+		return false
+	}
+
+	ipWait, err := cfg.LoginIPLimiter.Check(r.Context(), loginIPKey(cfg.clientIP(r)))
+	if err != nil {
+		log.Printf("failed to check login throttle: %s", err)
+		w.WriteHeader(500)
+		return false
+	}
+
+	wait := max(emailWait, ipWait)
+	if wait > 0 {
+		writeTooManyRequests(w, wait)
+		return false
+	}
+	return true
+}
+
+// recordLoginAttempt feeds the throttles and the login_attempts log admins
+// look at. A success only clears the account's failures: an address that
+// guesses at many accounts stays throttled even if one guess is right.
+func (cfg *ApiConfig) recordLoginAttempt(r *http.Request, email string, succeeded bool) {
+	ip := cfg.clientIP(r)
+
+	var err error
+	if succeeded {
+		err = cfg.LoginEmailLimiter.Succeed(r.Context(), loginEmailKey(email))
+	} else {
+		err = cfg.LoginEmailLimiter.Fail(r.Context(), loginEmailKey(email))
+		if err == nil {
+			err = cfg.LoginIPLimiter.Fail(r.Context(), loginIPKey(ip))
+		}
+	}
+	if err != nil {
+		log.Printf("failed to update login throttle: %s", err)
+	}
+
+	err = cfg.DB.AddLoginAttempt(r.Context(), database.AddLoginAttemptParams{
+		ID:        uuid.New(),
+		Email:     strings.ToLower(email),
+		IpAddress: ip,
+		UserAgent: r.UserAgent(),
+		Succeeded: succeeded,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to record login attempt: %s", err)
+	}
+}
+
+func writeTooManyRequests(w http.ResponseWriter, wait time.Duration) {
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	w.WriteHeader(http.StatusTooManyRequests)
+	w.Write([]byte(fmt.Sprintf("Too many attempts, try again in %d seconds", seconds)))
+}
+
+// clientIP returns the address of the client, see throttle.ClientIP.
+func (cfg *ApiConfig) clientIP(r *http.Request) string {
+	return throttle.ClientIP(r.RemoteAddr, r.Header.Values("X-Forwarded-For"), cfg.TrustedProxies)
+}
+
+// RunLoginAttemptPruner deletes login attempts older than
+// LoginAttemptRetention every interval until ctx is done.
+func (cfg *ApiConfig) RunLoginAttemptPruner(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := cfg.DB.DeleteLoginAttemptsBefore(ctx, time.Now().Add(-cfg.LoginAttemptRetention))
+		if err != nil {
+			log.Printf("failed to prune login attempts: %s", err)
+		}
+	}
+}
+
+func (cfg *ApiConfig) HandleListLoginAttempts(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	params := database.ListLoginAttemptsParams{
+		Email:     nullString(strings.ToLower(query.Get("email"))),
+		IpAddress: nullString(query.Get("ip")),
+		RowLimit:  100,
+	}
+
+	if limit := query.Get("limit"); limit != "" {
+		parsed, err := strconv.Atoi(limit)
+		if err != nil || parsed < 1 || parsed > 1000 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte("limit must be between 1 and 1000"))
+			return
+		}
+		params.RowLimit = int32(parsed)
+	}
+
+	rows, err := cfg.DB.ListLoginAttempts(r.Context(), params)
+	if err != nil {
+		log.Printf("failed to list login attempts: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	result := []LoginAttempt{}
+	for _, row := range rows {
+		result = append(result, LoginAttempt{
+			ID:        row.ID,
+			Email:     row.Email,
+			IPAddress: row.IpAddress,
+			UserAgent: row.UserAgent,
+			Succeeded: row.Succeeded,
+			CreatedAt: row.CreatedAt,
+		})
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Printf("failed to marshal login attempts: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// Purposes of the rows in one_time_tokens. A token is only ever accepted for
+// the purpose it was created for.
+const (
+	tokenPurposePasswordReset     = "password_reset"
+	tokenPurposeEmailVerification = "email_verification"
+	tokenPurposeLoginChallenge    = "login_challenge"
+	tokenPurposeMagicLink         = "magic_link"
+	tokenPurposeDataExport        = "data_export"
+	tokenPurposeAccountDeletion   = "account_deletion"
+)
+
+// createOneTimeToken stores the hash of a fresh single-use token and returns
+// the token itself, which is only ever handed to the user.
+func (cfg *ApiConfig) createOneTimeToken(ctx context.Context, purpose string, userID uuid.UUID, payload string, ttl time.Duration) (string, error) {
+	token, err := auth.MakeRefreshToken()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+	err = cfg.DB.CreateOneTimeToken(ctx, database.CreateOneTimeTokenParams{
+		TokenHash: auth.HashToken(token),
+		Purpose:   purpose,
+		UserID:    userID,
+		Payload:   payload,
+		CreatedAt: now,
+		ExpiresAt: now.Add(ttl),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// RunOneTimeTokenPruner deletes expired one-time tokens every interval until
+// ctx is done. Used tokens go once they expire as well.
+func (cfg *ApiConfig) RunOneTimeTokenPruner(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		err := cfg.DB.DeleteExpiredOneTimeTokens(ctx, time.Now())
+		if err != nil {
+			log.Printf("failed to prune one-time tokens: %s", err)
+		}
+	}
+}
@@ -0,0 +1,400 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+const (
+	loginChallengeTTL = 5 * time.Minute
+	// loginChallengeMaxAttempts caps how many codes can be tried with one
+	// challenge; after that the password has to be entered again.
+	loginChallengeMaxAttempts = 5
+	recoveryCodeCount         = 10
+	totpIssuer                = "Chirpy"
+)
+
+// startLoginChallenge answers a correct password for a 2FA-enabled account
+// with a short-lived challenge token instead of a session.
+func (cfg *ApiConfig) startLoginChallenge(w http.ResponseWriter, r *http.Request, user database.User) {
+	type response struct {
+		TwoFactorRequired bool   `json:"two_factor_required"`
+		ChallengeToken    string `json:"challenge_token"`
+	}
+
+	token, err := cfg.createOneTimeToken(r.Context(), tokenPurposeLoginChallenge, user.ID, "", loginChallengeTTL)
+	if err != nil {
+		log.Printf("failed to create login challenge: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	data, err := json.Marshal(response{
+		TwoFactorRequired: true,
+		ChallengeToken:    token,
+	})
+	if err != nil {
+		log.Printf("failed to marshal login challenge: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// HandleLoginTOTP completes a login started by HandleLogin with either a TOTP
+// code or one of the user's recovery codes.
+func (cfg *ApiConfig) HandleLoginTOTP(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		ChallengeToken string `json:"challenge_token"`
+		Code           string `json:"code"`
+		RecoveryCode   string `json:"recovery_code"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	tokenHash := auth.HashToken(params.ChallengeToken)
+	challenge, err := cfg.DB.GetOneTimeToken(r.Context(), database.GetOneTimeTokenParams{
+		TokenHash: tokenHash,
+		Purpose:   tokenPurposeLoginChallenge,
+		ExpiresAt: now,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	user, err := cfg.DB.GetUser(r.Context(), challenge.UserID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if !cfg.checkLoginThrottle(w, r, user.Email) {
+		return
+	}
+
+	if !cfg.checkCanLogIn(w, user) {
+		return
+	}
+
+	ok, err := cfg.verifySecondFactor(r, challenge.UserID, params.Code, params.RecoveryCode)
+	if err != nil {
+		log.Printf("failed to verify second factor: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		cfg.recordLoginAttempt(r, user.Email, false)
+		attempts, err := cfg.DB.AddOneTimeTokenAttempt(r.Context(), tokenHash)
+		if err == nil && attempts >= loginChallengeMaxAttempts {
+			cfg.DB.ConsumeOneTimeToken(r.Context(), database.ConsumeOneTimeTokenParams{
+				UsedAt:    sql.NullTime{Valid: true, Time: now},
+				TokenHash: tokenHash,
+				Purpose:   tokenPurposeLoginChallenge,
+			})
+		}
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Incorrect code"))
+		return
+	}
+
+	_, err = cfg.DB.ConsumeOneTimeToken(r.Context(), database.ConsumeOneTimeTokenParams{
+		UsedAt:    sql.NullTime{Valid: true, Time: now},
+		TokenHash: tokenHash,
+		Purpose:   tokenPurposeLoginChallenge,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	cfg.recordLoginAttempt(r, user.Email, true)
+	cfg.completeLogin(w, r, user)
+}
+
+func (cfg *ApiConfig) HandleEnrollTOTP(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	user, err := cfg.DB.GetUser(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	existing, err := cfg.DB.GetUserTOTP(r.Context(), userID)
+	if err == nil && existing.ConfirmedAt.Valid {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Two-factor authentication is already enabled"))
+		return
+	}
+
+	secret, err := auth.GenerateTOTPSecret()
+	if err != nil {
+		log.Printf("failed to generate totp secret: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	err = cfg.DB.UpsertUserTOTP(r.Context(), database.UpsertUserTOTPParams{
+		UserID:    userID,
+		Secret:    secret,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		log.Printf("failed to save totp secret: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	type response struct {
+		Secret     string `json:"secret"`
+		OtpauthURI string `json:"otpauth_uri"`
+	}
+
+	data, err := json.Marshal(response{
+		Secret:     secret,
+		OtpauthURI: auth.TOTPURI(secret, totpIssuer, user.Email),
+	})
+	if err != nil {
+		log.Printf("failed to marshal totp enrollment: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// HandleConfirmTOTP turns 2FA on once the user proves their authenticator
+// produces valid codes, and hands out a fresh set of recovery codes.
+func (cfg *ApiConfig) HandleConfirmTOTP(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Code string `json:"code"`
+	}
+
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	totp, err := cfg.DB.GetUserTOTP(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	if totp.ConfirmedAt.Valid {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Two-factor authentication is already enabled"))
+		return
+	}
+
+	now := time.Now()
+	step, ok := auth.ValidateTOTP(totp.Secret, params.Code, now)
+	if !ok {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Incorrect code"))
+		return
+	}
+
+	_, err = cfg.DB.UseTOTPStep(r.Context(), database.UseTOTPStepParams{
+		LastUsedStep: step,
+		UserID:       userID,
+	})
+	if err != nil {
+		log.Printf("failed to record totp step: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	err = cfg.DB.ConfirmUserTOTP(r.Context(), database.ConfirmUserTOTPParams{
+		ConfirmedAt: sql.NullTime{Valid: true, Time: now},
+		UserID:      userID,
+	})
+	if err != nil {
+		log.Printf("failed to confirm totp: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	codes, err := cfg.replaceRecoveryCodes(r, userID)
+	if err != nil {
+		log.Printf("failed to create recovery codes: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	type response struct {
+		RecoveryCodes []string `json:"recovery_codes"`
+	}
+
+	data, err := json.Marshal(response{RecoveryCodes: codes})
+	if err != nil {
+		log.Printf("failed to marshal recovery codes: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// HandleDisableTOTP turns 2FA off. Wrong codes count against the same
+// throttle as the 2FA login step, so a stolen session can't be used to guess
+// its way past the second factor.
+func (cfg *ApiConfig) HandleDisableTOTP(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Code         string `json:"code"`
+		RecoveryCode string `json:"recovery_code"`
+	}
+
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	totp, err := cfg.DB.GetUserTOTP(r.Context(), userID)
+	if err != nil || !totp.ConfirmedAt.Valid {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	user, err := cfg.DB.GetUser(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if !cfg.checkLoginThrottle(w, r, user.Email) {
+		return
+	}
+
+	ok, err := cfg.verifySecondFactor(r, userID, params.Code, params.RecoveryCode)
+	if err != nil {
+		log.Printf("failed to verify second factor: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if !ok {
+		cfg.recordLoginAttempt(r, user.Email, false)
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte("Incorrect code"))
+		return
+	}
+
+	err = cfg.DB.DeleteUserTOTP(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to disable totp: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	err = cfg.DB.DeleteRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		log.Printf("failed to delete recovery codes: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// verifySecondFactor checks a TOTP code, or a recovery code when no TOTP code
+// is given. Both are single-use: a TOTP code can't be replayed within its
+// validity window and a recovery code is burnt on success.
+func (cfg *ApiConfig) verifySecondFactor(r *http.Request, userID uuid.UUID, code, recoveryCode string) (bool, error) {
+	now := time.Now()
+
+	if code != "" {
+		totp, err := cfg.DB.GetUserTOTP(r.Context(), userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+
+		step, ok := auth.ValidateTOTP(totp.Secret, code, now)
+		if !ok {
+			return false, nil
+		}
+
+		used, err := cfg.DB.UseTOTPStep(r.Context(), database.UseTOTPStepParams{
+			LastUsedStep: step,
+			UserID:       userID,
+		})
+		if err != nil {
+			return false, err
+		}
+		return used == 1, nil
+	}
+
+	if recoveryCode != "" {
+		used, err := cfg.DB.UseRecoveryCode(r.Context(), database.UseRecoveryCodeParams{
+			UsedAt:   sql.NullTime{Valid: true, Time: now},
+			CodeHash: auth.HashToken(auth.NormalizeRecoveryCode(recoveryCode)),
+			UserID:   userID,
+		})
+		if err != nil {
+			return false, err
+		}
+		return used == 1, nil
+	}
+
+	return false, nil
+}
+
+func (cfg *ApiConfig) replaceRecoveryCodes(r *http.Request, userID uuid.UUID) ([]string, error) {
+	codes, err := auth.GenerateRecoveryCodes(recoveryCodeCount)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cfg.DB.DeleteRecoveryCodes(r.Context(), userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for _, code := range codes {
+		err = cfg.DB.CreateRecoveryCode(r.Context(), database.CreateRecoveryCodeParams{
+			CodeHash:  auth.HashToken(auth.NormalizeRecoveryCode(code)),
+			UserID:    userID,
+			CreatedAt: now,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return codes, nil
+}
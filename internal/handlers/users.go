@@ -1,12 +1,19 @@
 package handlers
 
 import (
+	"database/sql"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"log"
 	"net/http"
+	"time"
 
+	"github.com/HellYeahOmg/Chirpy/internal/account"
 	"github.com/HellYeahOmg/Chirpy/internal/auth"
 	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+	"github.com/lib/pq"
 )
 
 func (cfg *ApiConfig) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
@@ -31,7 +38,17 @@ func (cfg *ApiConfig) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hash, err := auth.HashPassword(params.Password)
+	if err := account.ValidateEmail(params.Email); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
+		return
+	}
+
+	if !cfg.checkPasswordPolicy(w, params.Password, params.Email) {
+		return
+	}
+
+	hash, err := cfg.Passwords.Hash(params.Password)
 	if err != nil {
 		log.Printf("failed to hash the password: %v", err)
 		w.WriteHeader(500)
@@ -50,12 +67,18 @@ func (cfg *ApiConfig) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	err = cfg.sendVerificationEmail(r, dbUser)
+	if err != nil {
+		log.Printf("failed to send verification email: %s", err)
+	}
+
 	responseBody := User{
-		ID:          dbUser.ID,
-		CreatedAt:   dbUser.CreatedAt,
-		UpdatedAt:   dbUser.UpdatedAt,
-		Email:       dbUser.Email,
-		IsChirpyRed: dbUser.IsChirpyRed.Bool,
+		ID:            dbUser.ID,
+		CreatedAt:     dbUser.CreatedAt,
+		UpdatedAt:     dbUser.UpdatedAt,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		IsChirpyRed:   dbUser.IsChirpyRed.Bool,
 	}
 
 	data, err := json.Marshal(responseBody)
@@ -67,59 +90,163 @@ func (cfg *ApiConfig) HandleCreateUser(w http.ResponseWriter, r *http.Request) {
 	w.Write(data)
 }
 
+// HandlerUpdateUser replaces the email and password. It takes the same
+// current_password as HandlePatchUser and goes through the same checks.
 func (cfg *ApiConfig) HandlerUpdateUser(w http.ResponseWriter, r *http.Request) {
-	accessToken, err := auth.GetBearerToken(r.Header)
-	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
-		return
+	type parameters struct {
+		Email           string `json:"email"`
+		Password        string `json:"password"`
+		CurrentPassword string `json:"current_password"`
 	}
 
-	id, err := auth.ValidateJWT(accessToken, cfg.JwtSecret)
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
 	if err != nil {
-		w.WriteHeader(http.StatusUnauthorized)
+		log.Printf("failed to parse params in HandlerUpdateUser: %s", err)
+		w.WriteHeader(500)
 		return
 	}
 
+	cfg.updateUser(w, r, account.Update{
+		Email:           &params.Email,
+		Password:        &params.Password,
+		CurrentPassword: params.CurrentPassword,
+	})
+}
+
+// HandlePatchUser updates only the fields present in the body.
+func (cfg *ApiConfig) HandlePatchUser(w http.ResponseWriter, r *http.Request) {
 	type parameters struct {
-		Email    string `json:"email"`
-		Password string `json:"password"`
+		Email           *string `json:"email"`
+		Password        *string `json:"password"`
+		CurrentPassword string  `json:"current_password"`
 	}
 
 	params := parameters{}
 	decoder := json.NewDecoder(r.Body)
-	err = decoder.Decode(&params)
+	err := decoder.Decode(&params)
 	if err != nil {
-		log.Printf("failed to parse params in HandlerUpdateUser: %s", err)
-		w.WriteHeader(500)
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	cfg.updateUser(w, r, account.Update{
+		Email:           params.Email,
+		Password:        params.Password,
+		CurrentPassword: params.CurrentPassword,
+	})
+}
+
+// updateUser applies an update of the caller's own account. A new email or
+// password has to come with the current password, see account.Update.Plan.
+// A new password logs out every session, since whoever knew the old one may
+// hold one of them.
+func (cfg *ApiConfig) updateUser(w http.ResponseWriter, r *http.Request, update account.Update) {
+	id := auth.MustPrincipal(r.Context()).UserID
+
+	dbUser, err := cfg.DB.GetUser(r.Context(), id)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	hash, err := auth.HashPassword(params.Password)
+	// Everything is checked before anything is written, so a rejected
+	// password doesn't leave a half-applied update behind.
+	plan, err := update.Plan(dbUser.Email)
 	if err != nil {
-		log.Printf("failed to hash the password: %v", err)
-		w.WriteHeader(500)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(err.Error()))
 		return
 	}
 
-	queryParams := database.UpdateUserParams{
-		Email:          params.Email,
-		HashedPassword: hash,
-		ID:             id,
+	if plan.EmailChanged || plan.ChangePassword {
+		if !cfg.checkLoginThrottle(w, r, dbUser.Email) {
+			return
+		}
+
+		if cfg.Passwords.Check(update.CurrentPassword, dbUser.HashedPassword) != nil {
+			cfg.recordLoginAttempt(r, dbUser.Email, false)
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte("Current password is incorrect"))
+			return
+		}
 	}
 
-	dbUser, err := cfg.DB.UpdateUser(r.Context(), queryParams)
-	if err != nil {
-		log.Printf("failed to create a new user: %s", err)
-		w.WriteHeader(500)
-		return
+	var hash string
+	if plan.ChangePassword {
+		if !cfg.checkPasswordPolicy(w, *update.Password, plan.Email) {
+			return
+		}
+
+		hash, err = cfg.Passwords.Hash(*update.Password)
+		if err != nil {
+			log.Printf("failed to hash the password: %v", err)
+			w.WriteHeader(500)
+			return
+		}
+	}
+
+	now := time.Now()
+	if plan.EmailChanged {
+		oldEmail := dbUser.Email
+		dbUser, err = cfg.DB.UpdateUserEmail(r.Context(), database.UpdateUserEmailParams{
+			Email:     plan.Email,
+			UpdatedAt: now,
+			ID:        id,
+		})
+		if isUniqueViolation(err) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte("Email is already in use"))
+			return
+		}
+		if err != nil {
+			log.Printf("failed to update email: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		err = cfg.sendVerificationEmail(r, dbUser)
+		if err != nil {
+			log.Printf("failed to send verification email: %s", err)
+		}
+
+		cfg.sendEmailChangedNotice(oldEmail, dbUser.Email)
+	}
+
+	if plan.ChangePassword {
+		err = cfg.DB.UpdateUserPassword(r.Context(), database.UpdateUserPasswordParams{
+			HashedPassword: hash,
+			UpdatedAt:      now,
+			ID:             id,
+		})
+		if err != nil {
+			log.Printf("failed to update password: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+		dbUser.UpdatedAt = now
+
+		err = cfg.DB.RevokeUserRefreshTokens(r.Context(), database.RevokeUserRefreshTokensParams{
+			RevokedAt: sql.NullTime{Valid: true, Time: now},
+			UserID:    id,
+		})
+		if err != nil {
+			log.Printf("failed to revoke refresh tokens after password change: %s", err)
+			w.WriteHeader(500)
+			return
+		}
+
+		cfg.revokeUserAccessTokens(r, id)
 	}
 
 	responseBody := User{
-		ID:          dbUser.ID,
-		CreatedAt:   dbUser.CreatedAt,
-		UpdatedAt:   dbUser.UpdatedAt,
-		Email:       dbUser.Email,
-		IsChirpyRed: dbUser.IsChirpyRed.Bool,
+		ID:            dbUser.ID,
+		CreatedAt:     dbUser.CreatedAt,
+		UpdatedAt:     dbUser.UpdatedAt,
+		Email:         dbUser.Email,
+		EmailVerified: dbUser.EmailVerifiedAt.Valid,
+		IsChirpyRed:   dbUser.IsChirpyRed.Bool,
 	}
 
 	data, err := json.Marshal(responseBody)
@@ -132,3 +259,21 @@ func (cfg *ApiConfig) HandlerUpdateUser(w http.ResponseWriter, r *http.Request)
 	w.WriteHeader(http.StatusOK)
 	w.Write(data)
 }
+
+// sendEmailChangedNotice tells the old address of an account that the
+// address was changed, in case the change wasn't the owner's doing.
+func (cfg *ApiConfig) sendEmailChangedNotice(oldEmail, newEmail string) {
+	cfg.sendMail(mail.Message{
+		To:      oldEmail,
+		Subject: "Your Chirpy email address was changed",
+		Body: fmt.Sprintf("The email address of your Chirpy account was changed to %s.\n\n"+
+			"If you didn't do this, reset your password and contact support.", newEmail),
+	})
+}
+
+// isUniqueViolation reports whether err is Postgres refusing a duplicate
+// value, such as an email that another user already has.
+func isUniqueViolation(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code == "23505"
+}
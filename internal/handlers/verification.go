@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	chirpymail "github.com/HellYeahOmg/Chirpy/internal/mail"
+)
+
+const emailVerificationTTL = 24 * time.Hour
+
+// sendVerificationEmail mails a token that proves ownership of the user's
+// current address. The address is stored with the token, so a link mailed
+// before an email change can't verify the new address.
+func (cfg *ApiConfig) sendVerificationEmail(r *http.Request, user database.User) error {
+	token, err := cfg.createOneTimeToken(r.Context(), tokenPurposeEmailVerification, user.ID, user.Email, emailVerificationTTL)
+	if err != nil {
+		return err
+	}
+
+	link := fmt.Sprintf("%s/app/verify-email?token=%s", cfg.BaseURL, url.QueryEscape(token))
+	cfg.sendMail(chirpymail.Message{
+		To:      user.Email,
+		Subject: "Verify your Chirpy email address",
+		Body: fmt.Sprintf("Follow this link within a day to verify your email address:\n%s\n\n"+
+			"Or use this token: %s", link, token),
+	})
+	return nil
+}
+
+func (cfg *ApiConfig) HandleVerifyEmail(w http.ResponseWriter, r *http.Request) {
+	type parameters struct {
+		Token string `json:"token"`
+	}
+
+	params := parameters{}
+	decoder := json.NewDecoder(r.Body)
+	err := decoder.Decode(&params)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	now := time.Now()
+	token, err := cfg.DB.ConsumeOneTimeToken(r.Context(), database.ConsumeOneTimeTokenParams{
+		UsedAt:    sql.NullTime{Valid: true, Time: now},
+		TokenHash: auth.HashToken(params.Token),
+		Purpose:   tokenPurposeEmailVerification,
+	})
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("Invalid or expired token"))
+		return
+	}
+
+	verified, err := cfg.DB.MarkEmailVerified(r.Context(), database.MarkEmailVerifiedParams{
+		EmailVerifiedAt: sql.NullTime{Valid: true, Time: now},
+		ID:              token.UserID,
+		Email:           token.Payload,
+	})
+	if err != nil {
+		log.Printf("failed to mark email as verified: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	if verified == 0 {
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte("The email address has changed since this token was sent"))
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (cfg *ApiConfig) HandleResendVerification(w http.ResponseWriter, r *http.Request) {
+	userID := auth.MustPrincipal(r.Context()).UserID
+
+	user, err := cfg.DB.GetUser(r.Context(), userID)
+	if err != nil {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if user.EmailVerifiedAt.Valid {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte("Email address is already verified"))
+		return
+	}
+
+	err = cfg.sendVerificationEmail(r, user)
+	if err != nil {
+		log.Printf("failed to send verification email: %s", err)
+		w.WriteHeader(500)
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
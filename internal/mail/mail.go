@@ -0,0 +1,15 @@
+package mail
+
+import "context"
+
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Mailer delivers messages to users. Handlers only depend on this interface
+// so that local development and tests can swap SMTP for a file or the log.
+type Mailer interface {
+	Send(ctx context.Context, msg Message) error
+}
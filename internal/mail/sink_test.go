@@ -0,0 +1,28 @@
+package mail
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestWriterMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	m := NewWriterMailer(&buf)
+
+	err := m.Send(context.Background(), Message{
+		To:      "user@example.com",
+		Subject: "Hello",
+		Body:    "token: abc123",
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for _, want := range []string{"To: user@example.com", "Subject: Hello", "token: abc123"} {
+		if !strings.Contains(buf.String(), want) {
+			t.Fatalf("Expected output to contain %q, got %q", want, buf.String())
+		}
+	}
+}
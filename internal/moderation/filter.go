@@ -0,0 +1,214 @@
+package moderation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Action is what happens to a chirp containing a listed word.
+type Action string
+
+const (
+	// ActionMask replaces the word with asterisks and publishes the chirp.
+	ActionMask Action = "mask"
+	// ActionHold publishes the chirp only after a moderator approved it.
+	ActionHold Action = "hold"
+	// ActionReject refuses the chirp.
+	ActionReject Action = "reject"
+)
+
+// Actions are ordered: when a text matches several words, the most severe
+// action applies.
+var actionRanks = map[Action]int{
+	ActionMask:   1,
+	ActionHold:   2,
+	ActionReject: 3,
+}
+
+func ParseAction(s string) (Action, error) {
+	action := Action(s)
+	if _, ok := actionRanks[action]; !ok {
+		return "", fmt.Errorf("unknown moderation action %q", s)
+	}
+	return action, nil
+}
+
+// mask replaces masked words in the text.
+const mask = "****"
+
+// spelledOutMinLength is how many single letters in a row are read as a
+// spelled out word ("f.o.r.n.a.x").
+const spelledOutMinLength = 3
+
+// spelledOutMaxGap is how many separator runes may sit between the letters
+// of a spelled out word.
+const spelledOutMaxGap = 2
+
+type Rule struct {
+	Word   string
+	Action Action
+}
+
+// Filter matches texts against a word list. It is immutable and safe for
+// concurrent use; build a new one when the list changes.
+type Filter struct {
+	// words holds the rules by key; words that only differ in repeated
+	// letters, such as "as" and "ass", share a key.
+	words map[string][]rule
+}
+
+type rule struct {
+	word   string
+	action Action
+	runs   []int
+}
+
+// matches reports whether text runs spell the rule's word, possibly
+// stretched out: every letter has to be repeated at least as often as in
+// the word, so "asss" is "ass" but "as" isn't.
+func (r rule) matches(runs []int) bool {
+	for i, n := range r.runs {
+		if runs[i] < n {
+			return false
+		}
+	}
+	return true
+}
+
+func NewFilter(rules []Rule) *Filter {
+	f := &Filter{words: map[string][]rule{}}
+	for _, r := range rules {
+		var chars []char
+		for _, w := range words(r.Word) {
+			chars = append(chars, w.chars...)
+		}
+		k, runs := key(chars)
+		if k == "" {
+			continue
+		}
+		f.words[k] = append(f.words[k], rule{word: r.Word, action: r.Action, runs: runs})
+	}
+	return f
+}
+
+// Match is one listed word found in a text. Start and End are byte offsets
+// into the checked text.
+type Match struct {
+	Word   string
+	Action Action
+	Start  int
+	End    int
+}
+
+type Result struct {
+	// Action is the most severe action of all matches, or empty when the
+	// text is clean.
+	Action Action
+	// Text is the checked text with every masked word replaced.
+	Text    string
+	Matches []Match
+}
+
+// Check looks for listed words in text. Words match whole, never as part of
+// a longer word, so "fornaxes" is not caught by "fornax".
+func (f *Filter) Check(text string) Result {
+	result := Result{Text: text}
+	if f == nil || len(f.words) == 0 {
+		return result
+	}
+
+	ws := words(text)
+	var matches []Match
+
+	for _, w := range ws {
+		if m, ok := f.match(w.chars); ok {
+			matches = append(matches, m)
+		} else if m, ok := f.match(w.trimmed()); ok {
+			matches = append(matches, m)
+		}
+	}
+
+	// Runs of single letters, such as "f o r n a x" or "f.o.r.n.a.x".
+	for i := 0; i < len(ws); {
+		j := i + 1
+		for j < len(ws) && len(ws[j].chars) == 1 && ws[j].gap <= spelledOutMaxGap && len(ws[j-1].chars) == 1 {
+			j++
+		}
+		if len(ws[i].chars) == 1 && j-i >= spelledOutMinLength {
+			matches = append(matches, f.matchSpelledOut(ws[i:j])...)
+		}
+		i = j
+	}
+
+	if len(matches) == 0 {
+		return result
+	}
+
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Start < matches[j].Start })
+
+	var b strings.Builder
+	last := 0
+	for _, m := range matches {
+		if actionRanks[m.Action] > actionRanks[result.Action] {
+			result.Action = m.Action
+		}
+		if m.Action != ActionMask || m.Start < last {
+			continue
+		}
+		b.WriteString(text[last:m.Start])
+		b.WriteString(mask)
+		last = m.End
+	}
+	b.WriteString(text[last:])
+
+	result.Text = b.String()
+	result.Matches = matches
+	return result
+}
+
+func (f *Filter) match(chars []char) (Match, bool) {
+	if len(chars) == 0 {
+		return Match{}, false
+	}
+
+	k, runs := key(chars)
+	var r rule
+	ok := false
+	for _, candidate := range f.words[k] {
+		if candidate.matches(runs) && (!ok || actionRanks[candidate.action] > actionRanks[r.action]) {
+			r, ok = candidate, true
+		}
+	}
+	if !ok {
+		return Match{}, false
+	}
+
+	return Match{
+		Word:   r.word,
+		Action: r.action,
+		Start:  chars[0].start,
+		End:    chars[len(chars)-1].end,
+	}, true
+}
+
+// matchSpelledOut finds listed words in a run of single letter words. The
+// longest match starting at a letter wins.
+func (f *Filter) matchSpelledOut(run []word) []Match {
+	chars := make([]char, len(run))
+	for i, w := range run {
+		chars[i] = w.chars[0]
+	}
+
+	var matches []Match
+	for i := 0; i < len(chars); i++ {
+		for j := len(chars); j > i+1; j-- {
+			if m, ok := f.match(chars[i:j]); ok {
+				matches = append(matches, m)
+				i = j - 1
+				break
+			}
+		}
+	}
+	return matches
+}
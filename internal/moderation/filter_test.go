@@ -0,0 +1,125 @@
+package moderation
+
+import "testing"
+
+func testFilter() *Filter {
+	return NewFilter([]Rule{
+		{Word: "kerfuffle", Action: ActionMask},
+		{Word: "sharbert", Action: ActionMask},
+		{Word: "fornax", Action: ActionHold},
+		{Word: "zorp", Action: ActionReject},
+	})
+}
+
+func TestFilter_Check(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		action Action
+		want   string
+	}{
+		{name: "clean", text: "I had something interesting for breakfast", want: "I had something interesting for breakfast"},
+		{name: "mask", text: "This is a kerfuffle opinion I need to share", action: ActionMask, want: "This is a **** opinion I need to share"},
+		{name: "case", text: "KERFUFFLE and Sharbert!", action: ActionMask, want: "**** and ****!"},
+		{name: "part of a word", text: "kerfuffles are fine", want: "kerfuffles are fine"},
+		{name: "leetspeak", text: "what a k3rfuffl3", action: ActionMask, want: "what a ****"},
+		{name: "symbols", text: "$harbert", action: ActionMask, want: "****"},
+		{name: "homoglyphs", text: "k\u0435rfuffle", action: ActionMask, want: "****"},
+		{name: "accents", text: "shärbért", action: ActionMask, want: "****"},
+		{name: "fullwidth", text: "ｋｅｒｆｕｆｆｌｅ", action: ActionMask, want: "****"},
+		{name: "zero width", text: "kerf\u200buffle!", action: ActionMask, want: "****!"},
+		{name: "combining marks", text: "kerfu\u0308ffle", action: ActionMask, want: "****"},
+		{name: "repeated letters", text: "kerrrfuuuffle", action: ActionMask, want: "****"},
+		{name: "spelled out", text: "a s-h-a-r-b-e-r-t day", action: ActionMask, want: "a **** day"},
+		{name: "spaced", text: "k e r f u f f l e", action: ActionMask, want: "****"},
+		{name: "mention", text: "hi @sharbert", action: ActionMask, want: "hi @****"},
+		{name: "hold", text: "fornax kerfuffle", action: ActionHold, want: "fornax ****"},
+		{name: "reject wins", text: "zorp fornax", action: ActionReject, want: "zorp fornax"},
+	}
+
+	f := testFilter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := f.Check(tt.text)
+			if result.Action != tt.action {
+				t.Fatalf("Expected action %q, got %q (%+v)", tt.action, result.Action, result.Matches)
+			}
+			if result.Text != tt.want {
+				t.Fatalf("Expected text %q, got %q", tt.want, result.Text)
+			}
+		})
+	}
+}
+
+func TestFilter_Empty(t *testing.T) {
+	var f *Filter
+	if result := f.Check("kerfuffle"); result.Action != "" || result.Text != "kerfuffle" {
+		t.Fatalf("Expected nil filter to pass text through, got %+v", result)
+	}
+}
+
+func TestNewFilter_MostSevereActionWins(t *testing.T) {
+	f := NewFilter([]Rule{
+		{Word: "fornax", Action: ActionReject},
+		{Word: "fornnax", Action: ActionMask},
+	})
+	if result := f.Check("fornax"); result.Action != ActionReject {
+		t.Fatalf("Expected reject, got %q", result.Action)
+	}
+}
+
+func TestFilter_RepeatedLettersOnlyStretch(t *testing.T) {
+	f := NewFilter([]Rule{
+		{Word: "ass", Action: ActionReject},
+		{Word: "hell", Action: ActionHold},
+	})
+
+	for _, text := range []string{
+		"as far as I know",
+		"a s k me",
+		"I said hel",
+		"he l lo",
+	} {
+		if result := f.Check(text); result.Action != "" {
+			t.Errorf("Check(%q): expected no match, got %q (%+v)", text, result.Action, result.Matches)
+		}
+	}
+
+	for text, want := range map[string]Action{
+		"ass":           ActionReject,
+		"asssss":        ActionReject,
+		"a s s":         ActionReject,
+		"what the hell": ActionHold,
+		"go to helllll": ActionHold,
+	} {
+		if result := f.Check(text); result.Action != want {
+			t.Errorf("Check(%q): expected %q, got %q", text, want, result.Action)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"Fornax":          "fornax",
+		"  f0rn@x ":       "fornax",
+		"ＳＨＡＲＢＥＲＴ":        "sharbert",
+		"kerf\u00aduffle": "kerfuffle",
+		"!!!":             "iii",
+	}
+	for in, want := range tests {
+		if got := Normalize(in); got != want {
+			t.Errorf("Normalize(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestParseAction(t *testing.T) {
+	for _, s := range []string{"mask", "hold", "reject"} {
+		if _, err := ParseAction(s); err != nil {
+			t.Fatalf("Expected %q to parse, got %v", s, err)
+		}
+	}
+	if _, err := ParseAction("delete"); err == nil {
+		t.Fatal("Expected error for unknown action, got none")
+	}
+}
@@ -0,0 +1,66 @@
+package moderation
+
+import (
+	"context"
+	"log"
+	"sync/atomic"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+)
+
+// List holds the filter built from the word list in Postgres. The filter is
+// compiled once per load, so checking a chirp never touches the database.
+// Changes made through another instance are picked up by Run.
+type List struct {
+	db     *database.Queries
+	filter atomic.Pointer[Filter]
+}
+
+func NewList(db *database.Queries) *List {
+	l := &List{db: db}
+	l.filter.Store(NewFilter(nil))
+	return l
+}
+
+// Load rebuilds the filter from the database.
+func (l *List) Load(ctx context.Context) error {
+	rows, err := l.db.ListModerationWords(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make([]Rule, 0, len(rows))
+	for _, row := range rows {
+		action, err := ParseAction(row.Action)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, Rule{Word: row.Word, Action: action})
+	}
+
+	l.filter.Store(NewFilter(rules))
+	return nil
+}
+
+// Check runs text through the current filter.
+func (l *List) Check(text string) Result {
+	return l.filter.Load().Check(text)
+}
+
+// Run reloads the word list every interval until ctx is done.
+func (l *List) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := l.Load(ctx); err != nil {
+				log.Printf("failed to reload moderation words: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package moderation
+
+import (
+	"strings"
+	"unicode"
+)
+
+// homoglyphs maps runes that are commonly used to disguise a letter to the
+// ASCII letter they imitate: accented Latin letters and look-alikes from the
+// Cyrillic and Greek alphabets. Input is lowercased before the lookup.
+var homoglyphs = map[rune]rune{}
+
+func init() {
+	for to, from := range map[rune]string{
+		'a': "àáâãäåāăąǎаα",
+		'b': "ƀβ",
+		'c': "çćĉċčс",
+		'd': "ďđ",
+		'e': "èéêëēĕėęěеεё",
+		'g': "ĝğġģ",
+		'h': "ĥħһ",
+		'i': "ìíîïĩīĭįıǐіιї",
+		'j': "ĵј",
+		'k': "ķкκ",
+		'l': "ĺļľŀł",
+		'n': "ñńņňŉη",
+		'o': "òóôõöøōŏőǒоο",
+		'p': "рρ",
+		'r': "ŕŗř",
+		's': "śŝşšſѕß",
+		't': "ţťŧτ",
+		'u': "ùúûüũūŭůűųǔυ",
+		'v': "ν",
+		'w': "ŵω",
+		'x': "хχ",
+		'y': "ýÿŷу",
+		'z': "źżž",
+	} {
+		for _, r := range from {
+			homoglyphs[r] = to
+		}
+	}
+}
+
+// leet maps digits and symbols that stand in for letters.
+var leet = map[rune]rune{
+	'0': 'o',
+	'1': 'i',
+	'3': 'e',
+	'4': 'a',
+	'5': 's',
+	'7': 't',
+	'8': 'b',
+	'9': 'g',
+	'@': 'a',
+	'$': 's',
+	'!': 'i',
+	'|': 'l',
+	'+': 't',
+}
+
+// invisible runes are dropped as if they weren't there; they are used to
+// split a word without changing how it looks.
+func invisible(r rune) bool {
+	switch r {
+	case '\u00ad', '\u200b', '\u200c', '\u200d', '\u2060', '\ufeff':
+		return true
+	}
+	return unicode.Is(unicode.Mn, r)
+}
+
+// char is one normalized rune of a text together with the byte range of the
+// original text it came from.
+type char struct {
+	r          rune
+	start, end int
+	// symbol is set for punctuation read as a letter, such as '@' for 'a'.
+	// Such runes may also just be punctuation next to a word.
+	symbol bool
+}
+
+// normalizeRune folds r to the lowercase ASCII letter it stands for. ok is
+// false for runes that separate words.
+func normalizeRune(r rune) (n rune, symbol, ok bool) {
+	// Fullwidth forms of ASCII, e.g. 'Ｆ'.
+	if r >= '！' && r <= '～' {
+		r -= 0xfee0
+	}
+	r = unicode.ToLower(r)
+
+	if to, found := homoglyphs[r]; found {
+		return to, false, true
+	}
+	if to, found := leet[r]; found {
+		return to, !unicode.IsDigit(r), true
+	}
+	if unicode.IsLetter(r) || unicode.IsDigit(r) {
+		return r, false, true
+	}
+	return 0, false, false
+}
+
+// words splits text into runs of normalized runes. A run ends at any rune
+// that isn't a letter, digit or leetspeak symbol; gap is the number of runes
+// between a word and the one before it.
+func words(text string) []word {
+	var result []word
+	var current []char
+	gap := 0
+
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, word{chars: current, gap: gap})
+			current = nil
+			gap = 0
+		}
+	}
+
+	for i, r := range text {
+		end := i + len(string(r))
+		if invisible(r) {
+			if len(current) > 0 {
+				current[len(current)-1].end = end
+			}
+			continue
+		}
+
+		n, symbol, ok := normalizeRune(r)
+		if !ok {
+			flush()
+			gap++
+			if r == '\n' {
+				// Spelled out words don't continue across lines.
+				gap += 100
+			}
+			continue
+		}
+		current = append(current, char{r: n, start: i, end: end, symbol: symbol})
+	}
+	flush()
+
+	return result
+}
+
+type word struct {
+	chars []char
+	gap   int
+}
+
+// trimmed drops symbols at either end of the word, which are more likely
+// punctuation than disguised letters, as in "@name" or "word!".
+func (w word) trimmed() []char {
+	chars := w.chars
+	for len(chars) > 0 && chars[0].symbol {
+		chars = chars[1:]
+	}
+	for len(chars) > 0 && chars[len(chars)-1].symbol {
+		chars = chars[:len(chars)-1]
+	}
+	return chars
+}
+
+// key builds the lookup key of a run of characters, with repeated letters
+// collapsed, and returns how often each letter of the key was repeated.
+// Stretched out words ("fooornax") share the key of the word, and have at
+// least as many of every letter.
+func key(chars []char) (string, []int) {
+	var b strings.Builder
+	var runs []int
+	var last rune
+	for _, c := range chars {
+		if len(runs) > 0 && c.r == last {
+			runs[len(runs)-1]++
+			continue
+		}
+		b.WriteRune(c.r)
+		runs = append(runs, 1)
+		last = c.r
+	}
+	return b.String(), runs
+}
+
+// Normalize returns the canonical form of a word as it is stored in a word
+// list: lowercase ASCII where possible, with homoglyphs and leetspeak
+// resolved and everything that isn't part of a word removed.
+func Normalize(s string) string {
+	var b strings.Builder
+	for _, w := range words(s) {
+		for _, c := range w.chars {
+			b.WriteRune(c.r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,187 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// mockIdP is a minimal provider: it issues a code for whatever challenge it
+// was last asked for, and signs ID tokens with claims set by the test.
+type mockIdP struct {
+	server    *httptest.Server
+	key       *rsa.PrivateKey
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate rsa key: %v", err)
+	}
+
+	idp := &mockIdP{key: key}
+	mux := http.NewServeMux()
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	mux.HandleFunc("GET /.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 idp.server.URL,
+			"authorization_endpoint": idp.server.URL + "/authorize",
+			"token_endpoint":         idp.server.URL + "/token",
+			"jwks_uri":               idp.server.URL + "/jwks",
+		})
+	})
+
+	mux.HandleFunc("GET /jwks", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": "idp-key",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if r.Form.Get("code") != "the-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != idp.challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		j := jwt.NewWithClaims(jwt.SigningMethodRS256, idp.claims)
+		j.Header["kid"] = "idp-key"
+		signed, err := j.SignedString(key)
+		if err != nil {
+			t.Errorf("Failed to sign id token: %v", err)
+		}
+		json.NewEncoder(w).Encode(map[string]string{"access_token": "x", "id_token": signed})
+	})
+
+	return idp
+}
+
+func (idp *mockIdP) provider(t *testing.T) *Provider {
+	t.Helper()
+	p, err := Discover(context.Background(), Config{
+		Issuer:      idp.server.URL,
+		ClientID:    "chirpy",
+		RedirectURL: "http://localhost:8080/api/login/oidc/callback",
+	}, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	return p
+}
+
+// authorize plays the browser leg: it remembers the challenge from the
+// authorization URL like the real provider would.
+func (idp *mockIdP) authorize(t *testing.T, p *Provider, nonce string) string {
+	t.Helper()
+	verifier, challenge, err := NewPKCE()
+	if err != nil {
+		t.Fatalf("Failed to create pkce pair: %v", err)
+	}
+
+	u, err := url.Parse(p.AuthCodeURL("state", nonce, challenge))
+	if err != nil {
+		t.Fatalf("Failed to parse auth url: %v", err)
+	}
+	if u.Query().Get("code_challenge_method") != "S256" {
+		t.Fatalf("Expected S256 challenge, got %s", u)
+	}
+	idp.challenge = u.Query().Get("code_challenge")
+	return verifier
+}
+
+func (idp *mockIdP) setClaims(nonce, aud string) {
+	idp.claims = jwt.MapClaims{
+		"iss":            idp.server.URL,
+		"sub":            "user-123",
+		"aud":            aud,
+		"exp":            time.Now().Add(time.Hour).Unix(),
+		"iat":            time.Now().Unix(),
+		"nonce":          nonce,
+		"email":          "jane@example.com",
+		"email_verified": true,
+	}
+}
+
+func TestProvider_CodeFlow(t *testing.T) {
+	idp := newMockIdP(t)
+	p := idp.provider(t)
+
+	verifier := idp.authorize(t, p, "n-1")
+	idp.setClaims("n-1", "chirpy")
+
+	raw, err := p.Exchange(context.Background(), "the-code", verifier)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	token, err := p.VerifyIDToken(context.Background(), raw, "n-1")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if token.Subject != "user-123" || token.Email != "jane@example.com" || !token.EmailVerified {
+		t.Fatalf("Unexpected id token: %+v", token)
+	}
+}
+
+func TestProvider_RejectsWrongVerifier(t *testing.T) {
+	idp := newMockIdP(t)
+	p := idp.provider(t)
+
+	idp.authorize(t, p, "n-1")
+	idp.setClaims("n-1", "chirpy")
+
+	if _, err := p.Exchange(context.Background(), "the-code", "not-the-verifier"); err == nil {
+		t.Fatal("Expected error for wrong code verifier, got none")
+	}
+}
+
+func TestProvider_RejectsBadClaims(t *testing.T) {
+	idp := newMockIdP(t)
+	p := idp.provider(t)
+
+	tests := []struct {
+		name  string
+		nonce string
+		aud   string
+	}{
+		{"wrong nonce", "other", "chirpy"},
+		{"wrong audience", "n-1", "someone-else"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier := idp.authorize(t, p, "n-1")
+			idp.setClaims(tt.nonce, tt.aud)
+
+			raw, err := p.Exchange(context.Background(), "the-code", verifier)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if _, err := p.VerifyIDToken(context.Background(), raw, "n-1"); err == nil {
+				t.Fatal("Expected error, got none")
+			}
+		})
+	}
+}
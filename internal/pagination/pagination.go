@@ -0,0 +1,154 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Cursor is a position in a list ordered by (created_at, id). Pages start
+// right after the cursor, so rows inserted while a client is scrolling
+// neither repeat nor shift the following pages.
+type Cursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// cursorVersion prefixes encoded cursors so the format can change without
+// misreading cursors that clients still hold.
+const cursorVersion = "v1"
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Encode returns the cursor in the opaque form handed to clients.
+// Timestamps keep microseconds, the precision Postgres stores.
+func (c Cursor) Encode() string {
+	raw := fmt.Sprintf("%s:%d:%s", cursorVersion, c.CreatedAt.UnixMicro(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeCursor(s string) (Cursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || parts[0] != cursorVersion {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return parsePosition(parts[1], parts[2])
+}
+
+func parsePosition(micros, id string) (Cursor, error) {
+	parsedMicros, err := strconv.ParseInt(micros, 10, 64)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	parsedID, err := uuid.Parse(id)
+	if err != nil {
+		return Cursor{}, ErrInvalidCursor
+	}
+
+	return Cursor{CreatedAt: time.UnixMicro(parsedMicros).UTC(), ID: parsedID}, nil
+}
+
+// RankCursor is a position in a list ordered by relevance first, such as
+// search results, with (created_at, id) breaking ties.
+type RankCursor struct {
+	Rank float32
+	Cursor
+}
+
+const rankCursorVersion = "r1"
+
+// Encode keeps the rank exact, so the database compares the rank it
+// computes again against the very same value.
+func (c RankCursor) Encode() string {
+	raw := fmt.Sprintf("%s:%s:%d:%s", rankCursorVersion,
+		strconv.FormatFloat(float64(c.Rank), 'g', -1, 32), c.CreatedAt.UnixMicro(), c.ID)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeRankCursor(s string) (RankCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return RankCursor{}, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 4 || parts[0] != rankCursorVersion {
+		return RankCursor{}, ErrInvalidCursor
+	}
+
+	parsedRank, err := strconv.ParseFloat(parts[1], 32)
+	if err != nil {
+		return RankCursor{}, ErrInvalidCursor
+	}
+
+	cursor, err := parsePosition(parts[2], parts[3])
+	if err != nil {
+		return RankCursor{}, err
+	}
+
+	return RankCursor{Rank: float32(parsedRank), Cursor: cursor}, nil
+}
+
+// PathCursor is a position in a depth-first walk of a tree, such as the
+// replies of a thread. It holds the sort keys from the root down to the
+// last node seen, exactly as the database produced them; they are only
+// compared, never interpreted.
+type PathCursor []string
+
+const pathCursorVersion = "p1"
+
+// pathSeparator cannot occur in a sort key, which is made of digits, a
+// colon and a UUID.
+const pathSeparator = ","
+
+func (c PathCursor) Encode() string {
+	raw := pathCursorVersion + pathSeparator + strings.Join(c, pathSeparator)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodePathCursor(s string) (PathCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	parts := strings.Split(string(raw), pathSeparator)
+	if len(parts) < 2 || parts[0] != pathCursorVersion {
+		return nil, ErrInvalidCursor
+	}
+
+	for _, key := range parts[1:] {
+		if key == "" {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	return PathCursor(parts[1:]), nil
+}
+
+// ParseLimit reads a page size, using def when s is empty. Sizes outside of
+// 1..max are an error rather than being clamped, so clients notice.
+func ParseLimit(s string, def, max int) (int, error) {
+	if s == "" {
+		return def, nil
+	}
+
+	limit, err := strconv.Atoi(s)
+	if err != nil || limit < 1 || limit > max {
+		return 0, fmt.Errorf("limit must be a number between 1 and %d", max)
+	}
+
+	return limit, nil
+}
@@ -0,0 +1,125 @@
+package pagination
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestCursor_RoundTrip(t *testing.T) {
+	c := Cursor{
+		CreatedAt: time.Date(2025, 3, 1, 12, 30, 0, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	decoded, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !decoded.CreatedAt.Equal(c.CreatedAt) || decoded.ID != c.ID {
+		t.Fatalf("Expected %+v, got %+v", c, decoded)
+	}
+}
+
+func TestCursor_DropsSubMicrosecondPrecision(t *testing.T) {
+	c := Cursor{CreatedAt: time.Date(2025, 3, 1, 0, 0, 0, 1999, time.UTC), ID: uuid.New()}
+
+	decoded, err := DecodeCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if want := c.CreatedAt.Truncate(time.Microsecond); !decoded.CreatedAt.Equal(want) {
+		t.Fatalf("Expected %v, got %v", want, decoded.CreatedAt)
+	}
+}
+
+func TestDecodeCursor_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"not base64!",
+		"djE6MTIz",                // v1:123
+		"djI6MTIzOmFiYw",          // v2:123:abc
+		"djE6YWJjOmFiYw",          // v1:abc:abc
+		"djE6MTIzOm5vdC1hLXV1aWQ", // v1:123:not-a-uuid
+	} {
+		if _, err := DecodeCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q): expected ErrInvalidCursor, got %v", s, err)
+		}
+	}
+}
+
+func TestRankCursor_RoundTrip(t *testing.T) {
+	c := RankCursor{
+		Rank: 0.0607927,
+		Cursor: Cursor{
+			CreatedAt: time.Date(2025, 3, 1, 12, 30, 0, 123456000, time.UTC),
+			ID:        uuid.New(),
+		},
+	}
+
+	decoded, err := DecodeRankCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if decoded.Rank != c.Rank || !decoded.CreatedAt.Equal(c.CreatedAt) || decoded.ID != c.ID {
+		t.Fatalf("Expected %+v, got %+v", c, decoded)
+	}
+}
+
+func TestDecodeRankCursor_RejectsPlainCursor(t *testing.T) {
+	plain := Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode()
+	if _, err := DecodeRankCursor(plain); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("Expected ErrInvalidCursor, got %v", err)
+	}
+	if _, err := DecodeCursor(RankCursor{Cursor: Cursor{ID: uuid.New()}}.Encode()); !errors.Is(err, ErrInvalidCursor) {
+		t.Fatalf("Expected ErrInvalidCursor, got %v", err)
+	}
+}
+
+func TestPathCursor_RoundTrip(t *testing.T) {
+	c := PathCursor{
+		"20250301123000123456:" + uuid.NewString(),
+		"20250301124500000001:" + uuid.NewString(),
+	}
+
+	decoded, err := DecodePathCursor(c.Encode())
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(decoded) != len(c) || decoded[0] != c[0] || decoded[1] != c[1] {
+		t.Fatalf("Expected %v, got %v", c, decoded)
+	}
+}
+
+func TestDecodePathCursor_Invalid(t *testing.T) {
+	plain := Cursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode()
+
+	for _, s := range []string{
+		"",
+		"not base64!",
+		plain,
+		"cDE",      // p1
+		"cDEs",     // p1,
+		"cDEsYSws", // p1,a,,
+	} {
+		if _, err := DecodePathCursor(s); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodePathCursor(%q): expected ErrInvalidCursor, got %v", s, err)
+		}
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	if limit, err := ParseLimit("", 20, 100); err != nil || limit != 20 {
+		t.Fatalf("Expected default 20, got %d (%v)", limit, err)
+	}
+	if limit, err := ParseLimit("100", 20, 100); err != nil || limit != 100 {
+		t.Fatalf("Expected 100, got %d (%v)", limit, err)
+	}
+	for _, s := range []string{"0", "-1", "101", "ten"} {
+		if _, err := ParseLimit(s, 20, 100); err == nil {
+			t.Errorf("ParseLimit(%q): expected error, got none", s)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package revocation
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+	"github.com/google/uuid"
+)
+
+// syncOverlap is how far back each sync looks past the previous one, so rows
+// written by instances with a slightly different clock aren't missed.
+const syncOverlap = 30 * time.Second
+
+// List is an in-memory copy of the revoked access tokens, kept in sync with
+// Postgres so revocations made on other instances are picked up. Checks only
+// ever read memory; the database is consulted by Sync.
+//
+// Revocations come in two kinds: an id, which is the jti of one token or the
+// sid of every token of a session, and a per-user cutoff, which revokes every
+// token of the user issued before it.
+type List struct {
+	// db is nil for a list that lives in memory only.
+	db *database.Queries
+	// tokenLifetime bounds how long an entry matters: past it, every token it
+	// could reject has expired on its own.
+	tokenLifetime time.Duration
+
+	mu       sync.RWMutex
+	revoked  map[uuid.UUID]time.Time
+	cutoffs  map[uuid.UUID]time.Time
+	syncedAt time.Time
+
+	now func() time.Time
+}
+
+func NewList(db *database.Queries, tokenLifetime time.Duration) *List {
+	return &List{
+		db:            db,
+		tokenLifetime: tokenLifetime,
+		revoked:       map[uuid.UUID]time.Time{},
+		cutoffs:       map[uuid.UUID]time.Time{},
+		now:           time.Now,
+	}
+}
+
+// IsRevoked implements auth.Revocations.
+//
+// Token timestamps only have second precision, so a token issued within the
+// same second as a cutoff is treated as issued before it. The client only
+// needs to refresh again.
+func (l *List) IsRevoked(userID uuid.UUID, issuedAt time.Time, tokenID, sessionID uuid.UUID) bool {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if _, ok := l.revoked[tokenID]; ok && tokenID != uuid.Nil {
+		return true
+	}
+	if _, ok := l.revoked[sessionID]; ok && sessionID != uuid.Nil {
+		return true
+	}
+
+	cutoff, ok := l.cutoffs[userID]
+	return ok && !issuedAt.After(cutoff)
+}
+
+// Revoke rejects the token or session with the given id from now on.
+func (l *List) Revoke(ctx context.Context, id uuid.UUID) error {
+	now := l.now()
+	expiresAt := now.Add(l.tokenLifetime)
+
+	l.mu.Lock()
+	l.revoked[id] = expiresAt
+	l.mu.Unlock()
+
+	if l.db == nil {
+		return nil
+	}
+	return l.db.AddAccessTokenRevocation(ctx, database.AddAccessTokenRevocationParams{
+		ID:        id,
+		CreatedAt: now,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// RevokeUser rejects every token of the user issued up to now.
+func (l *List) RevokeUser(ctx context.Context, userID uuid.UUID) error {
+	now := l.now()
+
+	l.mu.Lock()
+	l.cutoffs[userID] = now
+	l.mu.Unlock()
+
+	if l.db == nil {
+		return nil
+	}
+	return l.db.SetAccessTokenCutoff(ctx, database.SetAccessTokenCutoffParams{
+		UserID:        userID,
+		RevokedBefore: now,
+	})
+}
+
+// Sync loads the revocations written since the previous sync and forgets the
+// ones that no longer matter.
+func (l *List) Sync(ctx context.Context) error {
+	now := l.now()
+
+	l.mu.RLock()
+	since := l.syncedAt.Add(-syncOverlap)
+	l.mu.RUnlock()
+	if oldest := now.Add(-l.tokenLifetime); since.Before(oldest) {
+		since = oldest
+	}
+
+	var revocations []database.AccessTokenRevocation
+	var cutoffs []database.AccessTokenCutoff
+	if l.db != nil {
+		var err error
+		revocations, err = l.db.ListAccessTokenRevocations(ctx, database.ListAccessTokenRevocationsParams{
+			CreatedAt: since,
+			ExpiresAt: now,
+		})
+		if err != nil {
+			return err
+		}
+
+		cutoffs, err = l.db.ListAccessTokenCutoffs(ctx, since)
+		if err != nil {
+			return err
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, r := range revocations {
+		l.revoked[r.ID] = r.ExpiresAt
+	}
+	for _, c := range cutoffs {
+		if c.RevokedBefore.After(l.cutoffs[c.UserID]) {
+			l.cutoffs[c.UserID] = c.RevokedBefore
+		}
+	}
+
+	for id, expiresAt := range l.revoked {
+		if expiresAt.Before(now) {
+			delete(l.revoked, id)
+		}
+	}
+	for userID, cutoff := range l.cutoffs {
+		if cutoff.Add(l.tokenLifetime).Before(now) {
+			delete(l.cutoffs, userID)
+		}
+	}
+
+	l.syncedAt = now
+	return nil
+}
+
+// Run syncs the list every interval until ctx is done, and deletes expired
+// rows while at it.
+func (l *List) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := l.Sync(ctx); err != nil {
+			log.Printf("failed to sync access token revocations: %s", err)
+			continue
+		}
+
+		if l.db != nil {
+			err := l.db.DeleteExpiredAccessTokenRevocations(ctx, l.now())
+			if err != nil {
+				log.Printf("failed to delete expired access token revocations: %s", err)
+			}
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package revocation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func newTestList(now *time.Time) *List {
+	l := NewList(nil, time.Hour)
+	l.now = func() time.Time { return *now }
+	return l
+}
+
+func TestList_RevokeTokenAndSession(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestList(&now)
+	userID, tokenID, sessionID := uuid.New(), uuid.New(), uuid.New()
+
+	if l.IsRevoked(userID, now, tokenID, sessionID) {
+		t.Fatal("Expected token not to be revoked")
+	}
+
+	if err := l.Revoke(context.Background(), sessionID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if !l.IsRevoked(userID, now, tokenID, sessionID) {
+		t.Fatal("Expected token of revoked session to be revoked")
+	}
+	if l.IsRevoked(userID, now, uuid.New(), uuid.New()) {
+		t.Fatal("Expected tokens of other sessions not to be revoked")
+	}
+	if l.IsRevoked(userID, now, uuid.Nil, uuid.Nil) {
+		t.Fatal("Expected tokens without ids not to be revoked")
+	}
+}
+
+func TestList_RevokeUser(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestList(&now)
+	userID := uuid.New()
+
+	issuedBefore := now.Add(-time.Minute)
+	if err := l.RevokeUser(context.Background(), userID); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if !l.IsRevoked(userID, issuedBefore, uuid.New(), uuid.New()) {
+		t.Fatal("Expected token issued before the cutoff to be revoked")
+	}
+	if l.IsRevoked(userID, now.Add(time.Second), uuid.New(), uuid.New()) {
+		t.Fatal("Expected token issued after the cutoff to be valid")
+	}
+	if l.IsRevoked(uuid.New(), issuedBefore, uuid.New(), uuid.New()) {
+		t.Fatal("Expected other users not to be affected")
+	}
+}
+
+func TestList_SyncForgetsExpiredEntries(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	l := newTestList(&now)
+	userID, tokenID := uuid.New(), uuid.New()
+
+	l.Revoke(context.Background(), tokenID)
+	l.RevokeUser(context.Background(), userID)
+
+	now = now.Add(2 * time.Hour)
+	if err := l.Sync(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if len(l.revoked) != 0 || len(l.cutoffs) != 0 {
+		t.Fatalf("Expected expired entries to be dropped, got %v %v", l.revoked, l.cutoffs)
+	}
+}
@@ -0,0 +1,51 @@
+// Package session holds the rules for refresh tokens and the sessions they
+// belong to, apart from the HTTP handlers that apply them.
+package session
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+// RefreshResult says what to do with a refresh token that was presented.
+type RefreshResult int
+
+const (
+	// RefreshOK means the token may be rotated for a new one.
+	RefreshOK RefreshResult = iota
+	// RefreshReused means the token was already rotated or revoked. It has
+	// leaked, so the whole session has to be revoked.
+	RefreshReused
+	// RefreshExpired means the token ran out; the user has to log in again.
+	RefreshExpired
+)
+
+// CheckRefresh decides what happens to a refresh token. Reuse wins over
+// expiry: a replayed token is a leak even if it has expired since.
+func CheckRefresh(revoked bool, expiresAt, now time.Time) RefreshResult {
+	if revoked {
+		return RefreshReused
+	}
+	if now.After(expiresAt) {
+		return RefreshExpired
+	}
+	return RefreshOK
+}
+
+// maxUserAgentLength keeps the user agent stored with a session to what
+// browsers and apps actually send; anything longer is cut.
+const maxUserAgentLength = 512
+
+// UserAgent returns the user agent to store with a session, cut to
+// maxUserAgentLength bytes without splitting a character.
+func UserAgent(ua string) string {
+	if len(ua) <= maxUserAgentLength {
+		return ua
+	}
+
+	cut := maxUserAgentLength
+	for cut > 0 && !utf8.RuneStart(ua[cut]) {
+		cut--
+	}
+	return ua[:cut]
+}
@@ -0,0 +1,61 @@
+package session
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestCheckRefresh(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		revoked   bool
+		expiresAt time.Time
+		want      RefreshResult
+	}{
+		{"valid", false, now.Add(time.Hour), RefreshOK},
+		{"expires right now", false, now, RefreshOK},
+		{"expired", false, now.Add(-time.Second), RefreshExpired},
+		{"revoked", true, now.Add(time.Hour), RefreshReused},
+		{"revoked and expired", true, now.Add(-time.Hour), RefreshReused},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CheckRefresh(tt.revoked, tt.expiresAt, now); got != tt.want {
+				t.Errorf("CheckRefresh() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	long := strings.Repeat("a", maxUserAgentLength)
+
+	tests := []struct {
+		name string
+		ua   string
+		want string
+	}{
+		{"empty", "", ""},
+		{"short", "curl/8.0", "curl/8.0"},
+		{"at the limit", long, long},
+		{"too long", long + "b", long},
+		{"cut inside a character", long[:maxUserAgentLength-1] + "é", long[:maxUserAgentLength-1]},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UserAgent(tt.ua)
+			if got != tt.want {
+				t.Errorf("UserAgent() = %q, want %q", got, tt.want)
+			}
+			if !utf8.ValidString(got) {
+				t.Errorf("UserAgent() = %q is not valid UTF-8", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package throttle
+
+import (
+	"net"
+	"strings"
+)
+
+// ClientIP returns the address a request came from, for throttling by
+// address. trustedProxies is how many proxies in front of us append to
+// X-Forwarded-For; with none the header is ignored, since anyone can send
+// it. Each proxy appends the address it got the request from, so the
+// client's address is the trustedProxies-th entry from the right. Entries
+// further left are whatever the client sent and are never used.
+func ClientIP(remoteAddr string, forwardedFor []string, trustedProxies int) string {
+	if trustedProxies > 0 {
+		var hops []string
+		for _, header := range forwardedFor {
+			for _, hop := range strings.Split(header, ",") {
+				hops = append(hops, strings.TrimSpace(hop))
+			}
+		}
+
+		if len(hops) >= trustedProxies {
+			if hop := hops[len(hops)-trustedProxies]; hop != "" {
+				return hop
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+	return host
+}
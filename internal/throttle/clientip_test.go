@@ -0,0 +1,28 @@
+package throttle
+
+import "testing"
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name           string
+		forwardedFor   []string
+		trustedProxies int
+		want           string
+	}{
+		{"no proxy", nil, 0, "10.0.0.1"},
+		{"header ignored without proxy", []string{"203.0.113.7"}, 0, "10.0.0.1"},
+		{"one proxy", []string{"203.0.113.7"}, 1, "203.0.113.7"},
+		{"spoofed leading entry", []string{"198.51.100.1, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"spoofed entries over several headers", []string{"198.51.100.1", "198.51.100.2, 203.0.113.7"}, 1, "203.0.113.7"},
+		{"two proxies", []string{"198.51.100.1, 203.0.113.7, 192.0.2.10"}, 2, "203.0.113.7"},
+		{"fewer hops than proxies", []string{"203.0.113.7"}, 2, "10.0.0.1"},
+		{"missing header", nil, 1, "10.0.0.1"},
+		{"empty entry", []string{"198.51.100.1, "}, 1, "10.0.0.1"},
+	}
+
+	for _, tt := range tests {
+		if got := ClientIP("10.0.0.1:54321", tt.forwardedFor, tt.trustedProxies); got != tt.want {
+			t.Errorf("%s: ClientIP() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package throttle
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps failures in process memory. It is only correct when a
+// single instance serves all requests; use PostgresStore otherwise.
+type MemoryStore struct {
+	mu       sync.Mutex
+	failures map[string][]time.Time
+	// retention bounds how long failures are kept; it should be at least as
+	// long as the longest policy window using the store.
+	retention time.Duration
+}
+
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		failures:  map[string][]time.Time{},
+		retention: retention,
+	}
+}
+
+func (s *MemoryStore) Failures(ctx context.Context, key string, since time.Time) (int, time.Time, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	var last time.Time
+	for _, at := range s.failures[key] {
+		if at.After(since) {
+			count++
+			if at.After(last) {
+				last = at
+			}
+		}
+	}
+	return count, last, nil
+}
+
+func (s *MemoryStore) RecordFailure(ctx context.Context, key string, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := at.Add(-s.retention)
+	kept := s.failures[key][:0]
+	for _, t := range s.failures[key] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	s.failures[key] = append(kept, at)
+	return nil
+}
+
+func (s *MemoryStore) Prune(ctx context.Context, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	cutoff := now.Add(-s.retention)
+	for key, failures := range s.failures {
+		kept := failures[:0]
+		for _, t := range failures {
+			if t.After(cutoff) {
+				kept = append(kept, t)
+			}
+		}
+
+		if len(kept) == 0 {
+			delete(s.failures, key)
+		} else {
+			s.failures[key] = kept
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) Reset(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.failures, key)
+	return nil
+}
@@ -0,0 +1,53 @@
+package throttle
+
+import (
+	"context"
+	"time"
+
+	"github.com/HellYeahOmg/Chirpy/internal/database"
+)
+
+// PostgresStore keeps failures in the throttle_events table so that every
+// instance behind a load balancer sees the same counts.
+type PostgresStore struct {
+	db        *database.Queries
+	retention time.Duration
+}
+
+func NewPostgresStore(db *database.Queries, retention time.Duration) *PostgresStore {
+	return &PostgresStore{db: db, retention: retention}
+}
+
+func (s *PostgresStore) Failures(ctx context.Context, key string, since time.Time) (int, time.Time, error) {
+	row, err := s.db.CountThrottleEvents(ctx, database.CountThrottleEventsParams{
+		Key:       key,
+		CreatedAt: since,
+	})
+	if err != nil {
+		return 0, time.Time{}, err
+	}
+	return int(row.Failures), row.LastFailureAt, nil
+}
+
+func (s *PostgresStore) RecordFailure(ctx context.Context, key string, at time.Time) error {
+	err := s.db.DeleteThrottleEventsBefore(ctx, database.DeleteThrottleEventsBeforeParams{
+		Key:       key,
+		CreatedAt: at.Add(-s.retention),
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.db.AddThrottleEvent(ctx, database.AddThrottleEventParams{
+		Key:       key,
+		CreatedAt: at,
+	})
+}
+
+func (s *PostgresStore) Prune(ctx context.Context, now time.Time) error {
+	return s.db.DeleteExpiredThrottleEvents(ctx, now.Add(-s.retention))
+}
+
+func (s *PostgresStore) Reset(ctx context.Context, key string) error {
+	return s.db.DeleteThrottleEvents(ctx, key)
+}
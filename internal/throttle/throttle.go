@@ -0,0 +1,99 @@
+package throttle
+
+import (
+	"context"
+	"log"
+	"time"
+)
+
+// Store keeps failed attempts per key. Keys are opaque to the store; callers
+// namespace them, e.g. "login-email:user@example.com".
+type Store interface {
+	// Failures returns how many failures were recorded for key after since,
+	// and when the latest of them happened.
+	Failures(ctx context.Context, key string, since time.Time) (int, time.Time, error)
+	RecordFailure(ctx context.Context, key string, at time.Time) error
+	Reset(ctx context.Context, key string) error
+	// Prune forgets failures of every key that are past the store's
+	// retention at now, including keys that are never tried again.
+	Prune(ctx context.Context, now time.Time) error
+}
+
+// PruneEvery prunes store every interval until ctx is done.
+func PruneEvery(ctx context.Context, store Store, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if err := store.Prune(ctx, time.Now()); err != nil {
+			log.Printf("failed to prune throttle store: %s", err)
+		}
+	}
+}
+
+// Policy describes how a key is slowed down as failures pile up within
+// Window: the first FreeAttempts failures cost nothing, every failure after
+// that doubles the wait starting at BaseDelay (capped at MaxDelay), and
+// LockoutThreshold failures lock the key for LockoutDuration.
+type Policy struct {
+	Window           time.Duration
+	FreeAttempts     int
+	BaseDelay        time.Duration
+	MaxDelay         time.Duration
+	LockoutThreshold int
+	LockoutDuration  time.Duration
+}
+
+// RetryAfter returns how long a key with the given failures has to wait
+// before its next attempt, or zero if it may try right away.
+func (p Policy) RetryAfter(failures int, lastFailure, now time.Time) time.Duration {
+	var wait time.Duration
+	switch {
+	case p.LockoutThreshold > 0 && failures >= p.LockoutThreshold:
+		wait = p.LockoutDuration
+	case failures > p.FreeAttempts:
+		wait = p.BaseDelay
+		for i := p.FreeAttempts + 1; i < failures && wait < p.MaxDelay; i++ {
+			wait *= 2
+		}
+		wait = min(wait, p.MaxDelay)
+	default:
+		return 0
+	}
+
+	return max(lastFailure.Add(wait).Sub(now), 0)
+}
+
+type Limiter struct {
+	store  Store
+	policy Policy
+	now    func() time.Time
+}
+
+func NewLimiter(store Store, policy Policy) *Limiter {
+	return &Limiter{store: store, policy: policy, now: time.Now}
+}
+
+// Check returns how long key has to wait before it may be tried again.
+func (l *Limiter) Check(ctx context.Context, key string) (time.Duration, error) {
+	now := l.now()
+	failures, last, err := l.store.Failures(ctx, key, now.Add(-l.policy.Window))
+	if err != nil {
+		return 0, err
+	}
+	return l.policy.RetryAfter(failures, last, now), nil
+}
+
+func (l *Limiter) Fail(ctx context.Context, key string) error {
+	return l.store.RecordFailure(ctx, key, l.now())
+}
+
+func (l *Limiter) Succeed(ctx context.Context, key string) error {
+	return l.store.Reset(ctx, key)
+}
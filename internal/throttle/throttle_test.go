@@ -0,0 +1,108 @@
+package throttle
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+var testPolicy = Policy{
+	Window:           15 * time.Minute,
+	FreeAttempts:     3,
+	BaseDelay:        time.Second,
+	MaxDelay:         time.Minute,
+	LockoutThreshold: 10,
+	LockoutDuration:  15 * time.Minute,
+}
+
+func TestPolicy_RetryAfter(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{0, 0},
+		{3, 0},
+		{4, time.Second},
+		{5, 2 * time.Second},
+		{6, 4 * time.Second},
+		{9, 32 * time.Second},
+		{10, 15 * time.Minute},
+	}
+
+	for _, tt := range tests {
+		if got := testPolicy.RetryAfter(tt.failures, now, now); got != tt.want {
+			t.Fatalf("RetryAfter(%d) = %v, want %v", tt.failures, got, tt.want)
+		}
+	}
+
+	noLockout := testPolicy
+	noLockout.LockoutThreshold = 0
+	if got := noLockout.RetryAfter(20, now, now); got != time.Minute {
+		t.Fatalf("Expected delay to be capped at MaxDelay, got %v", got)
+	}
+
+	if got := testPolicy.RetryAfter(5, now.Add(-time.Second), now); got != time.Second {
+		t.Fatalf("Expected elapsed time to count towards the delay, got %v", got)
+	}
+	if got := testPolicy.RetryAfter(5, now.Add(-time.Hour), now); got != 0 {
+		t.Fatalf("Expected delay to have passed, got %v", got)
+	}
+}
+
+func TestLimiter_MemoryStore(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	l := NewLimiter(NewMemoryStore(time.Hour), testPolicy)
+	l.now = func() time.Time { return now }
+
+	for range 10 {
+		if err := l.Fail(ctx, "key"); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	}
+
+	wait, err := l.Check(ctx, "key")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if wait != 15*time.Minute {
+		t.Fatalf("Expected lockout, got %v", wait)
+	}
+
+	if wait, _ := l.Check(ctx, "other"); wait != 0 {
+		t.Fatalf("Expected other keys to be unaffected, got %v", wait)
+	}
+
+	now = now.Add(16 * time.Minute)
+	if wait, _ := l.Check(ctx, "key"); wait != 0 {
+		t.Fatalf("Expected failures outside the window to be ignored, got %v", wait)
+	}
+
+	l.Fail(ctx, "key")
+	l.Succeed(ctx, "key")
+	if count, _, _ := l.store.Failures(ctx, "key", time.Time{}); count != 0 {
+		t.Fatalf("Expected success to reset failures, got %d", count)
+	}
+}
+
+func TestMemoryStore_PruneEvictsIdleKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	s := NewMemoryStore(time.Hour)
+
+	s.RecordFailure(ctx, "old", now.Add(-2*time.Hour))
+	s.RecordFailure(ctx, "mixed", now.Add(-2*time.Hour))
+	s.RecordFailure(ctx, "mixed", now.Add(-time.Minute))
+
+	if err := s.Prune(ctx, now); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, ok := s.failures["old"]; ok {
+		t.Fatalf("Expected a key without recent failures to be evicted")
+	}
+	if count, _, _ := s.Failures(ctx, "mixed", time.Time{}); count != 1 {
+		t.Fatalf("Expected only the recent failure to be kept, got %d", count)
+	}
+}
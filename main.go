@@ -1,13 +1,19 @@
 package main
 
 import (
+	"context"
 	"database/sql"
 	"log"
 	"net/http"
 	"os"
+	"time"
 
+	"github.com/HellYeahOmg/Chirpy/internal/auth"
 	"github.com/HellYeahOmg/Chirpy/internal/database"
 	"github.com/HellYeahOmg/Chirpy/internal/handlers"
+	"github.com/HellYeahOmg/Chirpy/internal/mail"
+	"github.com/HellYeahOmg/Chirpy/internal/moderation"
+	"github.com/HellYeahOmg/Chirpy/internal/revocation"
 	"github.com/joho/godotenv"
 	_ "github.com/lib/pq"
 )
@@ -17,6 +23,12 @@ func main() {
 	dbURL := os.Getenv("DB_URL")
 	jwtSecret := os.Getenv("JWT_SECRET")
 	polkaKey := os.Getenv("POLKA_KEY")
+	jwtKeysDir := os.Getenv("JWT_KEYS_DIR")
+	jwtActiveKeyID := os.Getenv("JWT_ACTIVE_KEY_ID")
+	baseURL := os.Getenv("BASE_URL")
+	if baseURL == "" {
+		baseURL = "http://localhost:8080"
+	}
 	db, err := sql.Open("postgres", dbURL)
 	if err != nil {
 		log.Printf("failed to open db connection: %s", err)
@@ -25,13 +37,89 @@ func main() {
 
 	dbQueries := database.New(db)
 
+	keys := auth.NewHMACKeySet(jwtSecret)
+	if jwtKeysDir != "" {
+		keys, err = auth.LoadKeySet(jwtKeysDir, jwtActiveKeyID)
+		if err != nil {
+			log.Printf("failed to load jwt signing keys: %s", err)
+			panic(1)
+		}
+		if until := envTime("JWT_ACCEPT_HMAC_UNTIL"); !until.IsZero() {
+			if jwtSecret == "" {
+				log.Fatalf("JWT_ACCEPT_HMAC_UNTIL is set but JWT_SECRET is empty")
+			}
+			keys.WithHMACFallback(jwtSecret, until)
+			log.Printf("accepting HS256 access tokens signed with JWT_SECRET until %s", until.Format(time.RFC3339))
+		}
+	}
+
+	revocations := revocation.NewList(dbQueries, handlers.AccessTokenTTL)
+	err = revocations.Sync(context.Background())
+	if err != nil {
+		log.Printf("failed to load access token revocations: %s", err)
+		panic(1)
+	}
+	go revocations.Run(context.Background(), envDuration("REVOCATION_SYNC_INTERVAL", 5*time.Second))
+	keys.WithRevocations(revocations)
+
+	mailer, err := newMailer()
+	if err != nil {
+		log.Printf("failed to set up mail delivery: %s", err)
+		panic(1)
+	}
+
+	passwordPolicy, err := newPasswordPolicy()
+	if err != nil {
+		log.Printf("failed to set up password policy: %s", err)
+		panic(1)
+	}
+
+	oidcProvider, err := newOIDCProvider(baseURL)
+	if err != nil {
+		log.Printf("failed to set up oidc login: %s", err)
+		panic(1)
+	}
+
+	moderationList := moderation.NewList(dbQueries)
+	err = moderationList.Load(context.Background())
+	if err != nil {
+		log.Printf("failed to load moderation words: %s", err)
+		panic(1)
+	}
+	go moderationList.Run(context.Background(), envDuration("MODERATION_SYNC_INTERVAL", 30*time.Second))
+
+	loginEmailLimiter, loginIPLimiter := newLoginLimiters(dbQueries)
+
 	sm := http.NewServeMux()
 	config := handlers.ApiConfig{
-		DB:        dbQueries,
-		JwtSecret: jwtSecret,
-		PolkaKey:  polkaKey,
+		DB:             dbQueries,
+		Conn:           db,
+		Keys:           keys,
+		Passwords:      newPasswordHasher(),
+		PasswordPolicy: passwordPolicy,
+		PolkaKey:       polkaKey,
+		Mailer:         mailer,
+		BaseURL:        baseURL,
+
+		RequireVerifiedEmailToLogin: os.Getenv("REQUIRE_VERIFIED_EMAIL_LOGIN") == "true",
+		RequireVerifiedEmailToChirp: os.Getenv("REQUIRE_VERIFIED_EMAIL_CHIRPS") == "true",
+		LoginEmailLimiter:           loginEmailLimiter,
+		LoginIPLimiter:              loginIPLimiter,
+		MagicLinkLimiter:            newMagicLinkLimiter(dbQueries),
+		TrustedProxies:              trustedProxies(),
+		LoginAttemptRetention:       envDuration("LOGIN_ATTEMPT_RETENTION", 90*24*time.Hour),
+		OIDC:                        oidcProvider,
+		Revocations:                 revocations,
+		DeletionGracePeriod:         envDuration("ACCOUNT_DELETION_GRACE_PERIOD", 30*24*time.Hour),
+		Moderation:                  moderationList,
+		ChirpEditWindow:             envDuration("CHIRP_EDIT_WINDOW", 15*time.Minute),
+		DataExportRetention:         envDuration("DATA_EXPORT_RETENTION", 24*time.Hour),
 	}
 
+	go config.RunAccountPurger(context.Background(), envDuration("ACCOUNT_PURGE_INTERVAL", time.Hour))
+	go config.RunLoginAttemptPruner(context.Background(), time.Hour)
+	go config.RunOneTimeTokenPruner(context.Background(), time.Hour)
+
 	s := http.Server{
 		Handler: sm,
 		Addr:    ":8080",
@@ -40,26 +128,85 @@ func main() {
 	sm.Handle("/app/", config.MiddlewareMetricsInc(http.StripPrefix("/app/", http.FileServer(http.Dir("./")))))
 	sm.Handle("/app/assets", http.StripPrefix("/app/assets", http.FileServer(http.Dir("./assets/"))))
 
-	sm.HandleFunc("GET /admin/metrics", config.HandleMetrics)
-	sm.HandleFunc("POST /admin/reset", config.HandleReset)
+	sm.Handle("GET /admin/metrics", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleMetrics)))
+	sm.Handle("POST /admin/reset", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleReset)))
+	sm.Handle("GET /admin/users/{userId}/roles", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleGetUserRoles)))
+	sm.Handle("POST /admin/users/{userId}/roles", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleGrantRole)))
+	sm.Handle("DELETE /admin/users/{userId}/roles/{role}", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleRevokeRole)))
+	sm.Handle("POST /admin/users/{userId}/ban", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleBanUser)))
+	sm.Handle("DELETE /admin/users/{userId}/ban", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleUnbanUser)))
+	sm.Handle("GET /admin/moderation/words", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleListModerationWords)))
+	sm.Handle("PUT /admin/moderation/words/{word}", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandlePutModerationWord)))
+	sm.Handle("DELETE /admin/moderation/words/{word}", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleDeleteModerationWord)))
+	sm.Handle("GET /admin/chirps/held", config.MiddlewareRequireRole(auth.RoleModerator, http.HandlerFunc(config.HandleListHeldChirps)))
+	sm.Handle("POST /admin/chirps/{chirpId}/approve", config.MiddlewareRequireRole(auth.RoleModerator, http.HandlerFunc(config.HandleApproveChirp)))
+	sm.Handle("POST /admin/chirps/{chirpId}/reject", config.MiddlewareRequireRole(auth.RoleModerator, http.HandlerFunc(config.HandleRejectChirp)))
+	sm.Handle("GET /admin/login-attempts", config.MiddlewareRequireRole(auth.RoleAdmin, http.HandlerFunc(config.HandleListLoginAttempts)))
 
 	sm.HandleFunc("GET /api/healthz", handlers.HandleHealthz)
+	sm.HandleFunc("GET /.well-known/jwks.json", config.HandleJWKS)
 
 	sm.HandleFunc("POST /api/users", config.HandleCreateUser)
 
-	sm.HandleFunc("POST /api/chirps", config.HandleCreateChirp)
+	sm.Handle("POST /api/chirps", config.MiddlewareRequireScope(auth.ScopeChirpsWrite, http.HandlerFunc(config.HandleCreateChirp)))
 
-	sm.HandleFunc("GET /api/chirps", config.HandleGetChirps)
+	sm.Handle("GET /api/chirps", config.MiddlewareOptionalAuth(auth.ScopeChirpsRead, http.HandlerFunc(config.HandleGetChirps)))
 
-	sm.HandleFunc("GET /api/chirps/{chirpId}", config.HandleGetChirp)
+	sm.Handle("GET /api/chirps/search", config.MiddlewareOptionalAuth(auth.ScopeChirpsRead, http.HandlerFunc(config.HandleSearchChirps)))
+	sm.Handle("GET /api/chirps/{chirpId}", config.MiddlewareOptionalAuth(auth.ScopeChirpsRead, http.HandlerFunc(config.HandleGetChirp)))
 
 	sm.HandleFunc("POST /api/login", config.HandleLogin)
+	sm.HandleFunc("POST /api/login/2fa", config.HandleLoginTOTP)
+	sm.HandleFunc("POST /api/login/magic", config.HandleRequestMagicLink)
+	sm.HandleFunc("POST /api/login/magic/consume", config.HandleConsumeMagicLink)
+	sm.HandleFunc("GET /api/login/oidc", config.HandleOIDCLogin)
+	sm.HandleFunc("GET /api/login/oidc/callback", config.HandleOIDCCallback)
+	sm.Handle("POST /api/2fa/enroll", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleEnrollTOTP)))
+	sm.Handle("POST /api/2fa/confirm", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleConfirmTOTP)))
+	sm.Handle("POST /api/2fa/disable", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleDisableTOTP)))
 
 	sm.HandleFunc("POST /api/refresh", config.HandleRefresh)
 	sm.HandleFunc("POST /api/revoke", config.HandleRevoke)
-	sm.HandleFunc("PUT /api/users", config.HandlerUpdateUser)
-	sm.HandleFunc("DELETE /api/chirps/{chirpId}", config.HandleDeleteChirp)
+	sm.Handle("GET /api/sessions", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleListSessions)))
+	sm.Handle("DELETE /api/sessions", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleRevokeAllSessions)))
+	sm.Handle("DELETE /api/sessions/{sessionId}", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleRevokeSession)))
+	sm.Handle("POST /api/keys", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleCreateAPIKey)))
+	sm.Handle("GET /api/keys", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleListAPIKeys)))
+	sm.Handle("DELETE /api/keys/{keyId}", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleRevokeAPIKey)))
+	sm.Handle("PUT /api/users", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandlerUpdateUser)))
+	sm.Handle("PATCH /api/users", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandlePatchUser)))
+	sm.Handle("DELETE /api/users/me", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleDeleteAccount)))
+	sm.Handle("POST /api/users/me/deletion-token", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleRequestAccountDeletionToken)))
+	sm.Handle("POST /api/exports", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleCreateDataExport)))
+	sm.Handle("GET /api/exports/{exportId}", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleGetDataExport)))
+	sm.HandleFunc("GET /api/exports/{exportId}/download", config.HandleDownloadDataExport)
+	sm.HandleFunc("POST /api/users/verify", config.HandleVerifyEmail)
+	sm.Handle("POST /api/users/verify/resend", config.MiddlewareAuthenticate(http.HandlerFunc(config.HandleResendVerification)))
+	sm.HandleFunc("POST /api/password-reset", config.HandleRequestPasswordReset)
+	sm.HandleFunc("POST /api/password-reset/confirm", config.HandleConfirmPasswordReset)
+	sm.Handle("PUT /api/chirps/{chirpId}", config.MiddlewareRequireScope(auth.ScopeChirpsWrite, http.HandlerFunc(config.HandleUpdateChirp)))
+	sm.Handle("GET /api/chirps/{chirpId}/revisions", config.MiddlewareOptionalAuth(auth.ScopeChirpsRead, http.HandlerFunc(config.HandleListChirpRevisions)))
+	sm.Handle("GET /api/chirps/{chirpId}/thread", config.MiddlewareOptionalAuth(auth.ScopeChirpsRead, http.HandlerFunc(config.HandleGetChirpThread)))
+	sm.Handle("DELETE /api/chirps/{chirpId}", config.MiddlewareRequireScope(auth.ScopeChirpsWrite, http.HandlerFunc(config.HandleDeleteChirp)))
 	sm.HandleFunc("POST /api/polka/webhooks", config.HandlePolkaWebhook)
 
 	s.ListenAndServe()
 }
+
+// newMailer picks SMTP when SMTP_HOST is set, otherwise writes mails to
+// MAIL_FILE or, failing that, to the log.
+func newMailer() (mail.Mailer, error) {
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		return mail.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), os.Getenv("MAIL_FROM")), nil
+	}
+
+	if path := os.Getenv("MAIL_FILE"); path != "" {
+		return mail.NewFileMailer(path)
+	}
+
+	return mail.NewLogMailer(), nil
+}
@@ -0,0 +1,4 @@
+-- name: AddAccessTokenRevocation :exec
+insert into access_token_revocations (id, created_at, expires_at)
+values ( $1, $2, $3 )
+on conflict (id) do nothing;
@@ -0,0 +1,3 @@
+-- name: AddLoginAttempt :exec
+insert into login_attempts (id, email, ip_address, user_agent, succeeded, created_at)
+values ($1, $2, $3, $4, $5, $6);
@@ -0,0 +1,5 @@
+-- name: AddOneTimeTokenAttempt :one
+update one_time_tokens
+set attempts = attempts + 1
+where token_hash = $1
+returning attempts;
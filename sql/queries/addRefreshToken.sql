@@ -1,4 +1,5 @@
 -- name: AddRefreshToken :exec
 INSERT INTO refresh_tokens (
-  token, created_at, updated_at, user_id, experies_at  
-) VALUES ( $1, $2, $3, $4, $5 );
+  token, created_at, updated_at, user_id, experies_at, family_id,
+  session_started_at, last_used_at, user_agent, ip_address
+) VALUES ( $1, $2, $3, $4, $5, $6, $7, $8, $9, $10 );
@@ -0,0 +1,3 @@
+-- name: AddSubscriptionEvent :exec
+insert into subscription_events (id, user_id, event, created_at)
+values ($1, $2, $3, $4);
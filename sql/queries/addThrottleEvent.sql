@@ -0,0 +1,3 @@
+-- name: AddThrottleEvent :exec
+insert into throttle_events (key, created_at)
+values ($1, $2);
@@ -0,0 +1,4 @@
+-- name: ClaimRefreshToken :execrows
+UPDATE refresh_tokens
+  SET revoked_at = $1, updated_at = $1
+  WHERE token = $2 AND revoked_at IS NULL;
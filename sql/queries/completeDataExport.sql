@@ -0,0 +1,4 @@
+-- name: CompleteDataExport :exec
+update data_exports
+set status = 'ready', archive = $1, completed_at = $2, expires_at = $3
+where id = $4;
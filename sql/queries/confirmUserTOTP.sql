@@ -0,0 +1,4 @@
+-- name: ConfirmUserTOTP :exec
+update user_totp
+set confirmed_at = $1
+where user_id = $2;
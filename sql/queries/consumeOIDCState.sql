@@ -0,0 +1,4 @@
+-- name: ConsumeOIDCState :one
+delete from oidc_states
+where state = $1 and expires_at > $2
+returning *;
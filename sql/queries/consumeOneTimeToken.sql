@@ -0,0 +1,5 @@
+-- name: ConsumeOneTimeToken :one
+update one_time_tokens
+set used_at = $1
+where token_hash = $2 and purpose = $3 and used_at is null and expires_at > $1
+returning *;
@@ -0,0 +1,4 @@
+-- name: CountThrottleEvents :one
+select count(*) as failures, coalesce(max(created_at), 'epoch'::timestamp)::timestamp as last_failure_at
+from throttle_events
+where key = $1 and created_at > $2;
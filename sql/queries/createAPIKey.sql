@@ -0,0 +1,5 @@
+-- name: CreateAPIKey :one
+insert into api_keys (
+  id, user_id, name, key_hash, prefix, scopes, created_at, expires_at
+) values ( $1, $2, $3, $4, $5, $6, $7, $8 )
+returning *;
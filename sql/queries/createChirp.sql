@@ -1,5 +1,5 @@
 -- name: CreateChirp :one
 INSERT INTO chirps (
-  id, created_at, updated_at, body, user_id
-) VALUES ( $1, $2, $3, $4, $5 )
+  id, created_at, updated_at, body, user_id, status, in_reply_to
+) VALUES ( $1, $2, $3, $4, $5, $6, $7 )
 returning *;
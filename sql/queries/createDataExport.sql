@@ -0,0 +1,3 @@
+-- name: CreateDataExport :exec
+insert into data_exports (id, user_id, status, created_at, expires_at)
+values ($1, $2, 'pending', $3, $4);
@@ -0,0 +1,4 @@
+-- name: CreateOIDCState :exec
+insert into oidc_states (
+  state, nonce, code_verifier, created_at, expires_at
+) values ( $1, $2, $3, $4, $5 );
@@ -0,0 +1,4 @@
+-- name: CreateOneTimeToken :exec
+insert into one_time_tokens (
+  token_hash, purpose, user_id, payload, created_at, expires_at
+) values ( $1, $2, $3, $4, $5, $6 );
@@ -0,0 +1,3 @@
+-- name: CreateRecoveryCode :exec
+insert into totp_recovery_codes (code_hash, user_id, created_at)
+values ($1, $2, $3);
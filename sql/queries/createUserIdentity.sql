@@ -0,0 +1,4 @@
+-- name: CreateUserIdentity :exec
+insert into user_identities (
+  issuer, subject, user_id, email, created_at
+) values ( $1, $2, $3, $4, $5 );
@@ -1,3 +0,0 @@
--- name: DeleteChirp :exec
-DELETE FROM chirps
-WHERE id = $1;
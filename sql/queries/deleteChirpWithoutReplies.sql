@@ -0,0 +1,3 @@
+-- name: DeleteChirpWithoutReplies :execrows
+delete from chirps
+where id = $1 and reply_count = 0;
@@ -0,0 +1,3 @@
+-- name: DeleteExpiredAccessTokenRevocations :exec
+delete from access_token_revocations
+where expires_at < $1;
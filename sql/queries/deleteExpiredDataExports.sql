@@ -0,0 +1,3 @@
+-- name: DeleteExpiredDataExports :exec
+delete from data_exports
+where expires_at <= $1;
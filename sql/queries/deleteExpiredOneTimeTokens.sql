@@ -0,0 +1,3 @@
+-- name: DeleteExpiredOneTimeTokens :exec
+delete from one_time_tokens
+where expires_at <= $1;
@@ -0,0 +1,3 @@
+-- name: DeleteExpiredThrottleEvents :exec
+delete from throttle_events
+where created_at <= $1;
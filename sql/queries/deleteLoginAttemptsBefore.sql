@@ -0,0 +1,3 @@
+-- name: DeleteLoginAttemptsBefore :exec
+delete from login_attempts
+where created_at <= $1;
@@ -0,0 +1,3 @@
+-- name: DeleteModerationWord :execrows
+delete from moderation_words
+where word = $1;
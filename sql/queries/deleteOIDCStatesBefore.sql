@@ -0,0 +1,3 @@
+-- name: DeleteOIDCStatesBefore :exec
+delete from oidc_states
+where expires_at < $1;
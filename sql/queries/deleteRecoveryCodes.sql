@@ -0,0 +1,3 @@
+-- name: DeleteRecoveryCodes :exec
+delete from totp_recovery_codes
+where user_id = $1;
@@ -0,0 +1,4 @@
+-- name: DeleteScheduledUsers :many
+delete from users
+where deletion_scheduled_at <= $1
+returning id;
@@ -0,0 +1,3 @@
+-- name: DeleteThrottleEvents :exec
+delete from throttle_events
+where key = $1;
@@ -0,0 +1,3 @@
+-- name: DeleteThrottleEventsBefore :exec
+delete from throttle_events
+where key = $1 and created_at <= $2;
@@ -0,0 +1,3 @@
+-- name: DeleteUserTOTP :exec
+delete from user_totp
+where user_id = $1;
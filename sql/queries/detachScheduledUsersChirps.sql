@@ -0,0 +1,23 @@
+-- name: DetachScheduledUsersChirps :exec
+with doomed as (
+  -- Runs before DeleteScheduledUsers, in the same transaction: the chirps
+  -- with replies of the accounts about to go become tombstones without an
+  -- author, which the cascade from users leaves alone. The accounts are
+  -- locked so a login can't cancel the deletion halfway, and their chirps
+  -- so no reply to them sneaks in before they are deleted.
+  select id from users
+  where deletion_scheduled_at <= sqlc.arg(deletion_scheduled_at)
+  for update
+), locked as (
+  select id, reply_count from chirps
+  where user_id in (select id from doomed)
+  for update
+), detached as (
+  update chirps
+  set body = '', user_id = null,
+    deleted_at = coalesce(deleted_at, sqlc.arg(now)::timestamp), updated_at = sqlc.arg(now)::timestamp
+  where id in (select id from locked where reply_count > 0)
+  returning id
+)
+delete from chirp_revisions
+where chirp_id in (select id from detached);
@@ -0,0 +1,14 @@
+-- name: EditChirp :one
+with revision as (
+  -- Only published bodies become revisions: a held body was never
+  -- approved, so it must not show up once an edit publishes the chirp.
+  insert into chirp_revisions (id, chirp_id, body, created_at, replaced_at)
+  select sqlc.arg(revision_id), id, body, updated_at, sqlc.arg(updated_at) from chirps
+  where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is null
+    and created_at > sqlc.arg(editable_after) and status = 'published'
+)
+update chirps
+set body = sqlc.arg(body), status = sqlc.arg(status), updated_at = sqlc.arg(updated_at)
+where id = sqlc.arg(id) and user_id = sqlc.arg(user_id) and deleted_at is null
+  and created_at > sqlc.arg(editable_after)
+returning *;
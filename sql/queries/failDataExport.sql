@@ -0,0 +1,4 @@
+-- name: FailDataExport :exec
+update data_exports
+set status = 'failed', completed_at = $1
+where id = $2;
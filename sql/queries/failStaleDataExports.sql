@@ -0,0 +1,4 @@
+-- name: FailStaleDataExports :exec
+update data_exports
+set status = 'failed', completed_at = sqlc.arg(completed_at)
+where status = 'pending' and created_at <= sqlc.arg(started_before);
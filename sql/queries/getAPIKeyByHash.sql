@@ -0,0 +1,4 @@
+-- name: GetAPIKeyByHash :one
+select * from api_keys
+where key_hash = $1 and revoked_at is null and (expires_at is null or expires_at > $2)
+  and user_id in (select id from users where banned_at is null and deletion_scheduled_at is null);
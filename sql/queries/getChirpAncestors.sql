@@ -0,0 +1,13 @@
+-- name: GetChirpAncestors :many
+with recursive ancestors as (
+  select p.*, 1 as depth
+  from chirps p
+  where p.id = (select c.in_reply_to from chirps c where c.id = sqlc.arg(id))
+  union all
+  select p.*, a.depth + 1
+  from chirps p
+  join ancestors a on p.id = a.in_reply_to
+  where a.depth < sqlc.arg(max_depth)
+)
+select * from ancestors
+order by depth desc;
@@ -0,0 +1,19 @@
+-- name: GetChirpReplies :many
+with recursive replies as (
+  -- Held replies are walked like any other, so replies below them are not
+  -- lost; callers blank out the held ones a viewer may not see.
+  select c.*, 1 as depth,
+    array[(to_char(c.created_at, 'YYYYMMDDHH24MISSUS') || ':' || c.id::text) collate "C"] as path
+  from chirps c
+  where c.in_reply_to = sqlc.arg(id)
+  union all
+  select c.*, r.depth + 1,
+    r.path || ((to_char(c.created_at, 'YYYYMMDDHH24MISSUS') || ':' || c.id::text) collate "C")
+  from chirps c
+  join replies r on c.in_reply_to = r.id
+  where r.depth < sqlc.arg(max_depth)
+)
+select * from replies
+where sqlc.narg(after_path)::text[] is null or path > sqlc.narg(after_path)::text[]
+order by path
+limit sqlc.arg(row_limit);
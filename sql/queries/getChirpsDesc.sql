@@ -0,0 +1,10 @@
+-- name: GetChirpsDesc :many
+select * from chirps
+where status = 'published' and deleted_at is null
+  and (sqlc.narg(author_ids)::uuid[] is null or user_id = any(sqlc.narg(author_ids)::uuid[]))
+  and (sqlc.narg(since)::timestamp is null or created_at >= sqlc.narg(since))
+  and (sqlc.narg(until)::timestamp is null or created_at < sqlc.narg(until))
+  and (sqlc.narg(after_created_at)::timestamp is null
+    or (created_at, id) < (sqlc.narg(after_created_at), sqlc.narg(after_id)::uuid))
+order by created_at desc, id desc
+limit sqlc.arg(row_limit);
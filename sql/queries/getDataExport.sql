@@ -0,0 +1,4 @@
+-- name: GetDataExport :one
+select id, user_id, status, created_at, completed_at, expires_at
+from data_exports
+where id = $1 and user_id = $2;
@@ -0,0 +1,3 @@
+-- name: GetDataExportArchive :one
+select archive from data_exports
+where id = $1 and status = 'ready' and expires_at > $2;
@@ -0,0 +1,3 @@
+-- name: GetOneTimeToken :one
+select * from one_time_tokens
+where token_hash = $1 and purpose = $2 and used_at is null and expires_at > $3;
@@ -0,0 +1,5 @@
+-- name: GetPendingDataExport :one
+select id from data_exports
+where user_id = $1 and status = 'pending'
+order by created_at desc
+limit 1;
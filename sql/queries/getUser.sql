@@ -0,0 +1,3 @@
+-- name: GetUser :one
+select * from users
+where id = $1;
@@ -0,0 +1,3 @@
+-- name: GetUserIdentity :one
+select * from user_identities
+where issuer = $1 and subject = $2;
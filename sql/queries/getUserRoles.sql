@@ -0,0 +1,4 @@
+-- name: GetUserRoles :many
+select role from user_roles
+where user_id = $1
+order by role;
@@ -0,0 +1,3 @@
+-- name: GetUserTOTP :one
+select * from user_totp
+where user_id = $1;
@@ -0,0 +1,4 @@
+-- name: GrantRole :exec
+insert into user_roles (user_id, role, created_at)
+values ($1, $2, $3)
+on conflict do nothing;
@@ -0,0 +1,4 @@
+-- name: InvalidateOneTimeTokens :exec
+update one_time_tokens
+set used_at = $1
+where user_id = $2 and purpose = $3 and used_at is null;
@@ -0,0 +1,4 @@
+-- name: ListAPIKeys :many
+select * from api_keys
+where user_id = $1 and revoked_at is null
+order by created_at desc;
@@ -0,0 +1,3 @@
+-- name: ListAccessTokenCutoffs :many
+select * from access_token_cutoffs
+where revoked_before > $1;
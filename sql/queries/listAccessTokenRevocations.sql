@@ -0,0 +1,3 @@
+-- name: ListAccessTokenRevocations :many
+select * from access_token_revocations
+where created_at > $1 and expires_at > $2;
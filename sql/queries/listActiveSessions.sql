@@ -0,0 +1,5 @@
+-- name: ListActiveSessions :many
+SELECT family_id, session_started_at, last_used_at, user_agent, ip_address, experies_at
+FROM refresh_tokens
+WHERE user_id = $1 AND revoked_at IS NULL AND experies_at > $2
+ORDER BY last_used_at DESC;
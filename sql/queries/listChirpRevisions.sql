@@ -0,0 +1,3 @@
+-- name: ListChirpRevisions :many
+select * from chirp_revisions
+where chirp_id = $1;
@@ -0,0 +1,4 @@
+-- name: ListHeldChirps :many
+select * from chirps
+where status = 'held' and deleted_at is null
+order by created_at asc;
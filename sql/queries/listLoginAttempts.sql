@@ -0,0 +1,6 @@
+-- name: ListLoginAttempts :many
+select * from login_attempts
+where (sqlc.narg(email)::text is null or email = sqlc.narg(email))
+  and (sqlc.narg(ip_address)::text is null or ip_address = sqlc.narg(ip_address))
+order by created_at desc
+limit sqlc.arg(row_limit);
@@ -0,0 +1,3 @@
+-- name: ListModerationWords :many
+select * from moderation_words
+order by word;
@@ -0,0 +1,4 @@
+-- name: ListSubscriptionEvents :many
+select * from subscription_events
+where user_id = $1
+order by created_at asc;
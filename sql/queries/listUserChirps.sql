@@ -0,0 +1,4 @@
+-- name: ListUserChirps :many
+select * from chirps
+where user_id = $1 and deleted_at is null
+order by created_at asc;
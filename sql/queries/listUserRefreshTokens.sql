@@ -0,0 +1,5 @@
+-- name: ListUserRefreshTokens :many
+select family_id, created_at, last_used_at, experies_at, revoked_at, user_agent, ip_address
+from refresh_tokens
+where user_id = $1
+order by created_at asc;
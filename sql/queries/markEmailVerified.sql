@@ -0,0 +1,4 @@
+-- name: MarkEmailVerified :execrows
+update users
+set email_verified_at = $1, updated_at = $1
+where id = $2 and email = $3;
@@ -0,0 +1,4 @@
+-- name: RevokeAPIKey :execrows
+update api_keys
+set revoked_at = $1
+where id = $2 and user_id = $3 and revoked_at is null;
@@ -0,0 +1,3 @@
+-- name: RevokeRole :execrows
+delete from user_roles
+where user_id = $1 and role = $2;
@@ -0,0 +1,4 @@
+-- name: RevokeSession :execrows
+UPDATE refresh_tokens
+  SET revoked_at = $1, updated_at = $1
+  WHERE user_id = $2 AND family_id = $3 AND revoked_at IS NULL;
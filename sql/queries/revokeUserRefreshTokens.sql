@@ -0,0 +1,4 @@
+-- name: RevokeUserRefreshTokens :exec
+UPDATE refresh_tokens
+  SET revoked_at = $1, updated_at = $1
+  WHERE user_id = $2 AND revoked_at IS NULL;
@@ -0,0 +1,14 @@
+-- name: SearchChirps :many
+with matches as (
+  select c.id, c.created_at, c.updated_at, c.body, c.user_id, c.status, c.in_reply_to, c.reply_count, c.deleted_at,
+    ts_rank_cd(c.search_vector, websearch_to_tsquery('english', sqlc.arg(query))) as rank
+  from chirps c
+  where c.search_vector @@ websearch_to_tsquery('english', sqlc.arg(query))
+    and c.status = 'published' and c.deleted_at is null
+    and (sqlc.narg(author_ids)::uuid[] is null or c.user_id = any(sqlc.narg(author_ids)::uuid[]))
+)
+select id, created_at, updated_at, body, user_id, status, in_reply_to, reply_count, deleted_at, rank from matches
+where sqlc.narg(after_rank)::real is null
+  or (rank, created_at, id) < (sqlc.narg(after_rank)::real, sqlc.narg(after_created_at)::timestamp, sqlc.narg(after_id)::uuid)
+order by rank desc, created_at desc, id desc
+limit sqlc.arg(row_limit);
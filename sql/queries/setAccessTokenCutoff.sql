@@ -0,0 +1,4 @@
+-- name: SetAccessTokenCutoff :exec
+insert into access_token_cutoffs (user_id, revoked_before)
+values ( $1, $2 )
+on conflict (user_id) do update set revoked_before = excluded.revoked_before;
@@ -0,0 +1,4 @@
+-- name: SetChirpStatus :execrows
+update chirps
+set status = $1
+where id = $2 and status = 'held';
@@ -0,0 +1,4 @@
+-- name: SetUserBanned :execrows
+update users
+set banned_at = $1, updated_at = $2
+where id = $3;
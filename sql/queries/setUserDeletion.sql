@@ -0,0 +1,4 @@
+-- name: SetUserDeletion :execrows
+update users
+set deletion_scheduled_at = $1, updated_at = $2
+where id = $3;
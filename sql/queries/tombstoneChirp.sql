@@ -0,0 +1,8 @@
+-- name: TombstoneChirp :execrows
+with removed_revisions as (
+  delete from chirp_revisions
+  where chirp_id = sqlc.arg(id)
+)
+update chirps
+set body = '', deleted_at = sqlc.arg(deleted_at), updated_at = sqlc.arg(deleted_at)
+where id = sqlc.arg(id) and deleted_at is null;
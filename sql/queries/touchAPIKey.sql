@@ -0,0 +1,4 @@
+-- name: TouchAPIKey :exec
+update api_keys
+set last_used_at = $1
+where id = $2;
@@ -0,0 +1,5 @@
+-- name: UpdateUserEmail :one
+update users
+set email = $1, email_verified_at = null, updated_at = $2
+where id = $3
+returning *;
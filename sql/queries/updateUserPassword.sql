@@ -0,0 +1,4 @@
+-- name: UpdateUserPassword :exec
+update users
+set hashed_password = $1, updated_at = $2
+where id = $3;
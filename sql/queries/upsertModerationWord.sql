@@ -0,0 +1,6 @@
+-- name: UpsertModerationWord :one
+insert into moderation_words (word, action, created_at, updated_at)
+values ($1, $2, $3, $3)
+on conflict (word) do update
+set action = excluded.action, updated_at = excluded.updated_at
+returning *;
@@ -0,0 +1,6 @@
+-- name: UpsertUserTOTP :exec
+insert into user_totp (user_id, secret, created_at)
+values ($1, $2, $3)
+on conflict (user_id) do update
+set secret = excluded.secret, created_at = excluded.created_at, last_used_step = 0
+where user_totp.confirmed_at is null;
@@ -0,0 +1,4 @@
+-- name: UseRecoveryCode :execrows
+update totp_recovery_codes
+set used_at = $1
+where code_hash = $2 and user_id = $3 and used_at is null;
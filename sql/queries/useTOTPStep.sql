@@ -0,0 +1,4 @@
+-- name: UseTOTPStep :execrows
+update user_totp
+set last_used_step = $1
+where user_id = $2 and last_used_step < $1;
@@ -0,0 +1,20 @@
+-- +goose Up
+alter table refresh_tokens
+add column session_started_at timestamp not null default now(),
+add column last_used_at timestamp not null default now(),
+add column user_agent text not null default '',
+add column ip_address text not null default '';
+
+update refresh_tokens
+set session_started_at = created_at, last_used_at = created_at;
+
+create index refresh_tokens_user_id_idx on refresh_tokens(user_id);
+
+-- +goose Down
+drop index refresh_tokens_user_id_idx;
+
+alter table refresh_tokens
+drop column session_started_at,
+drop column last_used_at,
+drop column user_agent,
+drop column ip_address;
@@ -0,0 +1,10 @@
+-- +goose Up
+create table user_roles(
+  user_id uuid references users(id) on delete cascade not null,
+  role text not null check (role in ('moderator', 'admin')),
+  created_at timestamp not null,
+  primary key (user_id, role)
+);
+
+-- +goose Down
+drop table user_roles;
@@ -0,0 +1,15 @@
+-- +goose Up
+create table one_time_tokens(
+  token_hash text primary key,
+  purpose text not null,
+  user_id uuid references users(id) on delete cascade not null,
+  payload text not null default '',
+  created_at timestamp not null,
+  expires_at timestamp not null,
+  used_at timestamp
+);
+
+create index one_time_tokens_user_id_idx on one_time_tokens(user_id);
+
+-- +goose Down
+drop table one_time_tokens;
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table users
+add column email_verified_at timestamp;
+
+-- +goose Down
+alter table users
+drop column email_verified_at;
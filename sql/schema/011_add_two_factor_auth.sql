@@ -0,0 +1,27 @@
+-- +goose Up
+create table user_totp(
+  user_id uuid primary key references users(id) on delete cascade,
+  secret text not null,
+  created_at timestamp not null,
+  confirmed_at timestamp,
+  last_used_step bigint not null default 0
+);
+
+create table totp_recovery_codes(
+  code_hash text primary key,
+  user_id uuid references users(id) on delete cascade not null,
+  created_at timestamp not null,
+  used_at timestamp
+);
+
+create index totp_recovery_codes_user_id_idx on totp_recovery_codes(user_id);
+
+alter table one_time_tokens
+add column attempts integer not null default 0;
+
+-- +goose Down
+alter table one_time_tokens
+drop column attempts;
+
+drop table totp_recovery_codes;
+drop table user_totp;
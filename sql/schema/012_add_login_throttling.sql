@@ -0,0 +1,23 @@
+-- +goose Up
+create table throttle_events(
+  key text not null,
+  created_at timestamp not null
+);
+
+create index throttle_events_key_created_at_idx on throttle_events(key, created_at);
+
+create table login_attempts(
+  id uuid primary key,
+  email text not null,
+  ip_address text not null,
+  user_agent text not null,
+  succeeded boolean not null,
+  created_at timestamp not null
+);
+
+create index login_attempts_email_idx on login_attempts(email, created_at);
+create index login_attempts_ip_address_idx on login_attempts(ip_address, created_at);
+
+-- +goose Down
+drop table login_attempts;
+drop table throttle_events;
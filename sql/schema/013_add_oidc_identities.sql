@@ -0,0 +1,23 @@
+-- +goose Up
+create table oidc_states(
+  state text primary key,
+  nonce text not null,
+  code_verifier text not null,
+  created_at timestamp not null,
+  expires_at timestamp not null
+);
+
+create table user_identities(
+  issuer text not null,
+  subject text not null,
+  user_id uuid references users(id) on delete cascade not null,
+  email text not null,
+  created_at timestamp not null,
+  primary key (issuer, subject)
+);
+
+create index user_identities_user_id_idx on user_identities(user_id);
+
+-- +goose Down
+drop table user_identities;
+drop table oidc_states;
@@ -0,0 +1,18 @@
+-- +goose Up
+create table api_keys(
+  id uuid primary key,
+  user_id uuid references users(id) on delete cascade not null,
+  name text not null,
+  key_hash text not null unique,
+  prefix text not null,
+  scopes text[] not null,
+  created_at timestamp not null,
+  expires_at timestamp,
+  last_used_at timestamp,
+  revoked_at timestamp
+);
+
+create index api_keys_user_id_idx on api_keys(user_id);
+
+-- +goose Down
+drop table api_keys;
@@ -0,0 +1,20 @@
+-- +goose Up
+create table access_token_revocations(
+  -- The jti of a single access token or the sid of a whole session.
+  id uuid primary key,
+  created_at timestamp not null,
+  expires_at timestamp not null
+);
+
+create index access_token_revocations_created_at_idx on access_token_revocations(created_at);
+
+create table access_token_cutoffs(
+  user_id uuid primary key references users(id) on delete cascade,
+  revoked_before timestamp not null
+);
+
+create index access_token_cutoffs_revoked_before_idx on access_token_cutoffs(revoked_before);
+
+-- +goose Down
+drop table access_token_cutoffs;
+drop table access_token_revocations;
@@ -0,0 +1,7 @@
+-- +goose Up
+alter table users
+add column banned_at timestamp;
+
+-- +goose Down
+alter table users
+drop column banned_at;
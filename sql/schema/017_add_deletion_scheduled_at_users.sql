@@ -0,0 +1,13 @@
+-- +goose Up
+-- When set, the account is deleted once this time has passed.
+alter table users
+add column deletion_scheduled_at timestamp;
+
+create index users_deletion_scheduled_at_idx on users(deletion_scheduled_at)
+where deletion_scheduled_at is not null;
+
+-- +goose Down
+drop index users_deletion_scheduled_at_idx;
+
+alter table users
+drop column deletion_scheduled_at;
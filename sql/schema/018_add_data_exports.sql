@@ -0,0 +1,27 @@
+-- +goose Up
+create table subscription_events(
+  id uuid primary key,
+  user_id uuid references users(id) on delete cascade not null,
+  event text not null,
+  created_at timestamp not null
+);
+
+create index subscription_events_user_id_idx on subscription_events(user_id, created_at);
+
+-- archive is only filled in once status is 'ready'; expires_at tells when it
+-- is deleted again.
+create table data_exports(
+  id uuid primary key,
+  user_id uuid references users(id) on delete cascade not null,
+  status text not null check (status in ('pending', 'ready', 'failed')),
+  archive bytea,
+  created_at timestamp not null,
+  completed_at timestamp,
+  expires_at timestamp not null
+);
+
+create index data_exports_user_id_idx on data_exports(user_id, created_at);
+
+-- +goose Down
+drop table data_exports;
+drop table subscription_events;
@@ -0,0 +1,28 @@
+-- +goose Up
+-- word is stored normalized (see moderation.Normalize).
+create table moderation_words(
+  word text primary key,
+  action text not null check (action in ('mask', 'hold', 'reject')),
+  created_at timestamp not null,
+  updated_at timestamp not null
+);
+
+insert into moderation_words (word, action, created_at, updated_at) values
+  ('kerfuffle', 'mask', now(), now()),
+  ('sharbert', 'mask', now(), now()),
+  ('fornax', 'mask', now(), now());
+
+-- Held chirps are only shown to their author until a moderator approves them.
+alter table chirps
+add column status text not null default 'published' check (status in ('published', 'held'));
+
+create index chirps_held_idx on chirps(created_at)
+where status = 'held';
+
+-- +goose Down
+drop index chirps_held_idx;
+
+alter table chirps
+drop column status;
+
+drop table moderation_words;
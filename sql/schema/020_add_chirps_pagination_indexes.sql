@@ -0,0 +1,12 @@
+-- +goose Up
+-- Keyset pagination walks chirps in (created_at, id) order; these let every
+-- page start with an index seek, with or without an author filter.
+create index chirps_published_created_at_id_idx on chirps(created_at, id)
+where status = 'published';
+
+create index chirps_user_id_created_at_id_idx on chirps(user_id, created_at, id)
+where status = 'published';
+
+-- +goose Down
+drop index chirps_user_id_created_at_id_idx;
+drop index chirps_published_created_at_id_idx;
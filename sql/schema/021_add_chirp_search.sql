@@ -0,0 +1,32 @@
+-- +goose Up
+-- The search document of every chirp lives in its own table, kept current
+-- by a trigger, so the chirps table and its queries stay as they are.
+create table chirp_search_documents(
+  chirp_id uuid primary key references chirps(id) on delete cascade,
+  document tsvector not null
+);
+
+create index chirp_search_documents_document_idx on chirp_search_documents using gin(document);
+
+-- +goose StatementBegin
+create function chirps_update_search_document() returns trigger as $$
+begin
+  insert into chirp_search_documents (chirp_id, document)
+  values (new.id, to_tsvector('english', new.body))
+  on conflict (chirp_id) do update set document = excluded.document;
+  return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+create trigger chirps_search_document
+after insert or update of body on chirps
+for each row execute function chirps_update_search_document();
+
+insert into chirp_search_documents (chirp_id, document)
+select id, to_tsvector('english', body) from chirps;
+
+-- +goose Down
+drop trigger chirps_search_document on chirps;
+drop function chirps_update_search_document();
+drop table chirp_search_documents;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Every edit of a chirp keeps the body it replaced. created_at is when that
+-- body was written, replaced_at when the edit replaced it.
+create table chirp_revisions(
+  id uuid primary key,
+  chirp_id uuid references chirps(id) on delete cascade not null,
+  body text not null,
+  created_at timestamp not null,
+  replaced_at timestamp not null
+);
+
+create index chirp_revisions_chirp_id_idx on chirp_revisions(chirp_id, replaced_at);
+
+-- +goose Down
+drop table chirp_revisions;
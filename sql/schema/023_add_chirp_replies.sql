@@ -0,0 +1,44 @@
+-- +goose Up
+-- A chirp deleted while it has replies becomes a tombstone: its body is
+-- cleared and deleted_at set, but the row stays so the thread holds
+-- together. reply_count counts direct replies and is kept by a trigger.
+alter table chirps
+add column in_reply_to uuid references chirps(id) on delete set null,
+add column reply_count integer not null default 0,
+add column deleted_at timestamp;
+
+create index chirps_in_reply_to_idx on chirps(in_reply_to)
+where in_reply_to is not null;
+
+-- +goose StatementBegin
+create function chirps_update_reply_count() returns trigger as $$
+begin
+  if tg_op = 'INSERT' and new.in_reply_to is not null then
+    update chirps set reply_count = reply_count + 1
+    where id = new.in_reply_to;
+  elsif tg_op = 'DELETE' and old.in_reply_to is not null then
+    update chirps set reply_count = reply_count - 1
+    where id = old.in_reply_to;
+
+    -- A tombstone only exists for its replies; it goes with the last one.
+    delete from chirps
+    where id = old.in_reply_to and deleted_at is not null and reply_count = 0;
+  end if;
+  return null;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+create trigger chirps_reply_count
+after insert or delete on chirps
+for each row execute function chirps_update_reply_count();
+
+-- +goose Down
+drop trigger chirps_reply_count on chirps;
+drop function chirps_update_reply_count();
+drop index chirps_in_reply_to_idx;
+
+alter table chirps
+drop column deleted_at,
+drop column reply_count,
+drop column in_reply_to;
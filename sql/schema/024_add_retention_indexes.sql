@@ -0,0 +1,9 @@
+-- +goose Up
+-- Old throttle events and login attempts are deleted by age across all
+-- keys, which the existing per-key indexes don't help with.
+create index throttle_events_created_at_idx on throttle_events(created_at);
+create index login_attempts_created_at_idx on login_attempts(created_at);
+
+-- +goose Down
+drop index login_attempts_created_at_idx;
+drop index throttle_events_created_at_idx;
@@ -0,0 +1,5 @@
+-- +goose Up
+create index one_time_tokens_expires_at_idx on one_time_tokens(expires_at);
+
+-- +goose Down
+drop index one_time_tokens_expires_at_idx;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- When an account is purged, its chirps that have replies stay behind as
+-- tombstones without an author, so the replies of other users keep their
+-- place in the thread. Only tombstones can lose their author.
+alter table chirps
+alter column user_id drop not null,
+add constraint chirps_user_id_or_deleted check (user_id is not null or deleted_at is not null);
+
+-- +goose Down
+delete from chirps
+where user_id is null;
+
+alter table chirps
+drop constraint chirps_user_id_or_deleted,
+alter column user_id set not null;
@@ -0,0 +1,16 @@
+-- +goose Up
+-- Accounts from before email verification existed count as verified, or
+-- REQUIRE_VERIFIED_EMAIL_LOGIN would lock them out with no way to get a
+-- verification link. They are the ones created before migration 010 was
+-- applied; accounts created since then went through verification.
+update users
+set email_verified_at = created_at
+where email_verified_at is null
+  and created_at < (
+    select min(tstamp) from goose_db_version
+    where version_id = 10 and is_applied
+  );
+
+-- +goose Down
+-- Nothing to undo: the backfilled accounts can't be told apart from
+-- accounts that verified their address.
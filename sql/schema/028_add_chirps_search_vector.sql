@@ -0,0 +1,42 @@
+-- +goose Up
+-- The search document becomes a generated column of chirps, so Postgres
+-- keeps it current without a trigger and a second table to join.
+drop trigger chirps_search_document on chirps;
+drop function chirps_update_search_document();
+drop table chirp_search_documents;
+
+alter table chirps
+add column search_vector tsvector generated always as (to_tsvector('english', body)) stored;
+
+create index chirps_search_vector_idx on chirps using gin(search_vector);
+
+-- +goose Down
+drop index chirps_search_vector_idx;
+
+alter table chirps
+drop column search_vector;
+
+create table chirp_search_documents(
+  chirp_id uuid primary key references chirps(id) on delete cascade,
+  document tsvector not null
+);
+
+create index chirp_search_documents_document_idx on chirp_search_documents using gin(document);
+
+-- +goose StatementBegin
+create function chirps_update_search_document() returns trigger as $$
+begin
+  insert into chirp_search_documents (chirp_id, document)
+  values (new.id, to_tsvector('english', new.body))
+  on conflict (chirp_id) do update set document = excluded.document;
+  return new;
+end;
+$$ language plpgsql;
+-- +goose StatementEnd
+
+create trigger chirps_search_document
+after insert or update of body on chirps
+for each row execute function chirps_update_search_document();
+
+insert into chirp_search_documents (chirp_id, document)
+select id, to_tsvector('english', body) from chirps;